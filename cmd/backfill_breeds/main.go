@@ -0,0 +1,34 @@
+// backfill_breeds fills the canonical breed columns added by migration 000002
+// for cats created before breed metadata was stored. Run it once after `migrate up`.
+package main
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"spyCat/config"
+	"spyCat/database"
+	"spyCat/service"
+)
+
+func main() {
+	db := database.NewDatabase()
+	if db == nil {
+		log.Fatal().Msg("Database is unavailable")
+	}
+
+	breeds, _, err := service.NewBreedProvider(config.LoadENV(".env"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error configuring breed provider")
+	}
+
+	catService := service.NewCatService(database.NewCatDatabase(db), validator.New(), breeds, service.PayrollPolicy{})
+	updated, unmatched, err := catService.BackfillBreeds()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Breed backfill stopped after %d cats", updated)
+	}
+
+	for _, cat := range unmatched {
+		log.Warn().Msgf("Cat %d (%s) has unknown breed %q, fix it with PATCH /cats/%d", cat.ID, cat.Name, cat.Breed, cat.ID)
+	}
+	log.Info().Msgf("Breed backfill finished: %d cats updated, %d unmatched", updated, len(unmatched))
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/caarlos0/env/v9"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
+	"time"
 )
 
 type Config struct {
@@ -15,6 +16,24 @@ type Config struct {
 
 	DBHost       string `env:"DB_HOST"`
 	PostgresHost string `env:"POSTGRES_HOST"`
+
+	BreedProvider  string        `env:"BREED_PROVIDER" envDefault:"chain"`
+	CatAPIURL      string        `env:"CAT_API_URL" envDefault:"https://api.thecatapi.com/v1/breeds"`
+	BreedCacheTTL  time.Duration `env:"BREED_CACHE_TTL" envDefault:"24h"`
+	BreedCacheFile string        `env:"BREED_CACHE_FILE" envDefault:"breeds_cache.json"`
+
+	BreedVerifyInterval    time.Duration `env:"BREED_VERIFY_INTERVAL" envDefault:"10m"`
+	SalaryScheduleInterval time.Duration `env:"SALARY_SCHEDULE_INTERVAL" envDefault:"1h"`
+
+	// Ranks lists name:min_experience:min_completed_missions:salary_min-salary_max from the lowest rank up
+	Ranks                 string        `env:"RANKS" envDefault:"recruit:0:0:1000-3000,field_agent:2:3:2500-6000,senior_agent:5:10:5000-10000,master_spy:10:25:8000-20000"`
+	RankSalaryCurrency    string        `env:"RANK_SALARY_CURRENCY" envDefault:"USD"`
+	RankPromotionInterval time.Duration `env:"RANK_PROMOTION_INTERVAL" envDefault:"1h"`
+
+	// RaiseApprovalThreshold is the raise in percent above which a supervisor has to approve the change, 0 disables approvals
+	RaiseApprovalThreshold float64 `env:"RAISE_APPROVAL_THRESHOLD" envDefault:"10"`
+	// RaiseApprovalWindow is how far back raises add up when checked against RaiseApprovalThreshold
+	RaiseApprovalWindow time.Duration `env:"RAISE_APPROVAL_WINDOW" envDefault:"8760h"`
 }
 
 var cfg *Config
@@ -2,18 +2,49 @@ package database
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
 	"spyCat/database/models"
+	"strings"
 	"time"
 )
 
 type CatDatabaseInterface interface {
-	SelectAll() (*[]models.Cat, error)
+	SelectAll(filter models.CatFilter) ([]models.Cat, int, error)
 	SelectByID(id int) (*models.Cat, error)
 	Insert(cat models.Cat) (int, error)
-	Update(catID int, salary float64) error
-	Delete(id int) error
+	InsertMany(cats []models.Cat) ([]int, int, error)
+	Update(change *models.SalaryChange) error
+	ScheduleSalaryChange(change *models.SalaryChange) error
+	SelectSalaryHistory(catID int) ([]models.SalaryChange, error)
+	ApplyDueSalaryChanges() (int, error)
+	UpdateProfile(catID int, patch models.CatPatch) (*models.Cat, error)
+	SelectWithoutBreedDetails() ([]models.Cat, error)
+	UpdateBreed(catID int, breed models.Breed) error
+	SelectByBreedVerification(status models.BreedVerification) ([]models.Cat, error)
+	SetBreedVerification(catID int, status models.BreedVerification) error
+	Delete(catID int, deletion models.CatDeletion) ([]int, error)
+	SelectActiveMissions(catID int) ([]models.Mission, error)
+	Restore(id int) error
+	SelectAvailability(catID int) (*models.CatAvailability, error)
+	Search(query string, limit int) ([]models.CatMatch, error)
+	SelectSimilar(name, breed string) ([]models.CatMatch, error)
+	SelectRankStats(catID int) (*models.RankStats, error)
+	SelectAllRankStats() ([]models.RankStats, error)
+	RecordRankChange(stats models.RankStats, toRank string) (bool, error)
+	SelectPromotions(catID int) ([]models.Promotion, error)
+	SelectSalaryChange(id int) (*models.SalaryChange, error)
+	SelectSalaryChangesByStatus(status models.SalaryChangeStatus) ([]models.SalaryChange, error)
+	ReviewSalaryChange(id int, status models.SalaryChangeStatus, review models.SalaryReview) (*models.SalaryChange, error)
 }
 
+var (
+	ErrCatHasActiveMissions      = errors.New("cat has active missions")
+	ErrReplacementCatUnavailable = errors.New("replacement cat is unavailable")
+	ErrCatUnavailable            = errors.New("cat is unavailable")
+)
+
 type CatDatabase struct {
 	*Database
 }
@@ -22,55 +53,243 @@ func NewCatDatabase(Conn *Database) *CatDatabase {
 	return &CatDatabase{Conn}
 }
 
-func (cd *CatDatabase) SelectAll() (*[]models.Cat, error) {
+// catExperienceExpr is the cat's experience: prior experience plus the full years since the hire date,
+// or the stored years_of_experience for cats without a hire date
+const catExperienceExpr = `(CASE WHEN hire_date IS NULL THEN years_of_experience
+	ELSE prior_experience + date_part('year', age(CURRENT_DATE, hire_date))::int END)`
+
+// catSortColumns maps accepted sort keys to the columns they order by
+var catSortColumns = map[string]string{
+	"id":                  "id",
+	"name":                "name",
+	"years_of_experience": catExperienceExpr,
+	"breed":               "breed",
+	"salary":              "salary",
+	"created_at":          "created_at",
+}
+
+func IsValidCatSort(sortBy string) bool {
+	_, ok := catSortColumns[sortBy]
+	return ok
+}
+
+func catFilterClause(filter models.CatFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	addCond := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Breed != "" {
+		addCond("LOWER(breed) = LOWER($%d)", strings.TrimSpace(filter.Breed))
+	}
+	if filter.BreedVerification != "" {
+		addCond("breed_verification = $%d", filter.BreedVerification)
+	}
+	if filter.MinExperience != nil {
+		addCond(catExperienceExpr+" >= $%d", *filter.MinExperience)
+	}
+	if filter.MaxExperience != nil {
+		addCond(catExperienceExpr+" <= $%d", *filter.MaxExperience)
+	}
+	// salaries in different currencies cannot be compared, bounds only match cats paid in their currency
+	if filter.MinSalary != nil {
+		addCond("salary_currency = $%d", filter.MinSalary.Currency)
+		addCond("salary >= $%d", filter.MinSalary.Decimal())
+	}
+	if filter.MaxSalary != nil {
+		addCond("salary_currency = $%d", filter.MaxSalary.Currency)
+		addCond("salary <= $%d", filter.MaxSalary.Decimal())
+	}
+
+	// every listed skill is required, MinProficiency applies to each of them
+	minProficiency := models.MinProficiency
+	if filter.MinProficiency != nil {
+		minProficiency = *filter.MinProficiency
+	}
+	for _, skill := range filter.Skills {
+		args = append(args, strings.TrimSpace(skill), minProficiency)
+		conds = append(conds, fmt.Sprintf(`EXISTS (SELECT 1 FROM cat_skills cs JOIN skills s ON s.id = cs.skill_id
+			WHERE cs.cat_id = spy_cats.id AND LOWER(s.name) = LOWER($%d) AND cs.proficiency >= $%d)`, len(args)-1, len(args)))
+	}
+	if filter.Available != nil {
+		busy := `EXISTS (SELECT 1 FROM missions m WHERE m.cat_id = spy_cats.id AND m.` + activeMissionCondition + `)`
+		if *filter.Available {
+			conds = append(conds, "NOT "+busy, "deleted_at IS NULL")
+		} else {
+			conds = append(conds, busy)
+		}
+	}
+	if !filter.IncludeArchived {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (cd *CatDatabase) SelectAll(filter models.CatFilter) ([]models.Cat, int, error) {
 	var createdAt, updatedAt time.Time
-	var cats []models.Cat
+	cats := []models.Cat{}
 
-	query := `SELECT id, name, years_of_experience, breed, salary, created_at, updated_at FROM spy_cats`
-	rows, err := cd.Connection.Query(query)
+	where, args := catFilterClause(filter)
+
+	var total int
+	err := cd.Connection.QueryRow(`SELECT COUNT(*) FROM spy_cats`+where, args...).Scan(&total)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	sortColumn, ok := catSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = "id"
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT id, name, %s, hire_date, prior_experience, breed, salary, salary_currency, breed_verification, created_at, updated_at, deleted_at FROM spy_cats%s
+              ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d`, catExperienceExpr, where, sortColumn, direction, direction, len(args)-1, len(args))
+	rows, err := cd.Connection.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var cat models.Cat
+		var salary moneyDest
+		var hireDate, deletedAt sql.NullTime
 
-		if err := rows.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary, &createdAt, &updatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &hireDate, &cat.PriorExperience, &cat.Breed, &salary.amount, &salary.currency, &cat.BreedVerification, &createdAt, &updatedAt, &deletedAt); err != nil {
+			return nil, 0, err
+		}
+		if hireDate.Valid {
+			cat.HireDate = hireDate.Time.Format(models.DateLayout)
+		}
+		if cat.Salary, err = salary.value(); err != nil {
+			return nil, 0, err
 		}
 
 		cat.CreatedAt = createdAt.Format("15:04:05 02:01:06")
 		cat.UpdatedAt = updatedAt.Format("15:04:05 02:01:06")
+		if deletedAt.Valid {
+			cat.DeletedAt = deletedAt.Time.Format("15:04:05 02:01:06")
+		}
 		cats = append(cats, cat)
 	}
 
-	return &cats, nil
+	return cats, total, rows.Err()
 }
 
-func (cd *CatDatabase) SelectByID(id int) (*models.Cat, error) {
+const catProfileColumns = `id, name, ` + catExperienceExpr + `, hire_date, prior_experience, breed, salary, salary_currency, breed_verification,
+              created_at, updated_at, deleted_at, breed_id, breed_origin, breed_temperament, breed_life_span`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanCatProfile scans catProfileColumns, BreedDetails stays nil for cats not matched to the catalog yet
+func scanCatProfile(row rowScanner) (*models.Cat, error) {
 	var cat models.Cat
 	var createdAt, updatedAt time.Time
+	var breedID, origin, temperament, lifeSpan sql.NullString
+	var salary moneyDest
+	var hireDate, deletedAt sql.NullTime
 
-	query := `SELECT id, name, years_of_experience, breed, salary, created_at, updated_at 
-              FROM spy_cats WHERE id = $1`
-	err := cd.Connection.QueryRow(query, id).Scan(
-		&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary, &createdAt, &updatedAt)
+	err := row.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &hireDate, &cat.PriorExperience, &cat.Breed, &salary.amount, &salary.currency,
+		&cat.BreedVerification, &createdAt, &updatedAt, &deletedAt, &breedID, &origin, &temperament, &lifeSpan)
 	if err != nil {
 		return nil, err
 	}
+	if hireDate.Valid {
+		cat.HireDate = hireDate.Time.Format(models.DateLayout)
+	}
+	if cat.Salary, err = salary.value(); err != nil {
+		return nil, err
+	}
 
 	cat.CreatedAt = createdAt.Format("15:04:05 02:01:06")
 	cat.UpdatedAt = updatedAt.Format("15:04:05 02:01:06")
+	if deletedAt.Valid {
+		cat.DeletedAt = deletedAt.Time.Format("15:04:05 02:01:06")
+	}
+	if breedID.Valid {
+		cat.BreedDetails = &models.Breed{
+			ID:          breedID.String,
+			Name:        cat.Breed,
+			Origin:      origin.String,
+			Temperament: temperament.String,
+			LifeSpan:    lifeSpan.String,
+		}
+	}
 
 	return &cat, nil
 }
 
+func (cd *CatDatabase) SelectByID(id int) (*models.Cat, error) {
+	query := `SELECT ` + catProfileColumns + ` FROM spy_cats WHERE id = $1`
+	return scanCatProfile(cd.Connection.QueryRow(query, id))
+}
+
 func (cd *CatDatabase) Insert(cat models.Cat) (int, error) {
+	return insertCat(cd.Connection, cat)
+}
+
+// InsertMany inserts all cats in one transaction, either every cat is created or none is.
+// When the database refuses a cat the index of that cat is returned with the error, otherwise -1
+func (cd *CatDatabase) InsertMany(cats []models.Cat) ([]int, int, error) {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return nil, -1, err
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, 0, len(cats))
+	for i, cat := range cats {
+		id, err := insertCat(tx, cat)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			return nil, i, err
+		} else if err != nil {
+			return nil, -1, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, -1, err
+	}
+	return ids, -1, nil
+}
+
+func insertCat(q queryRower, cat models.Cat) (int, error) {
 	var id int
-	query := `INSERT INTO spy_cats (name, years_of_experience, breed, salary) 
-              VALUES ($1, $2, $3, $4) RETURNING id`
-	err := cd.Connection.QueryRow(query, cat.Name, cat.YearsOfExperience, cat.Breed, cat.Salary).Scan(&id)
+	var breed models.Breed
+	if cat.BreedDetails != nil {
+		breed = *cat.BreedDetails
+	}
+	if cat.BreedVerification == "" {
+		cat.BreedVerification = models.BreedVerificationVerified
+	}
+
+	query := `INSERT INTO spy_cats (name, years_of_experience, hire_date, prior_experience, breed, salary, salary_currency, breed_verification,
+                  breed_id, breed_origin, breed_temperament, breed_life_span) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+	err := q.QueryRow(query, cat.Name, cat.YearsOfExperience, nullString(cat.HireDate), cat.PriorExperience, cat.Breed, cat.Salary.Decimal(),
+		cat.Salary.Currency, cat.BreedVerification,
+		nullString(breed.ID), nullString(breed.Origin), nullString(breed.Temperament), nullString(breed.LifeSpan)).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -78,9 +297,97 @@ func (cd *CatDatabase) Insert(cat models.Cat) (int, error) {
 	return id, nil
 }
 
-func (cd *CatDatabase) Update(catID int, salary float64) error {
-	query := `UPDATE spy_cats SET salary = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $3`
-	result, err := cd.Connection.Exec(query, salary, catID)
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (cd *CatDatabase) UpdateProfile(catID int, patch models.CatPatch) (*models.Cat, error) {
+	var sets []string
+	var args []interface{}
+
+	addSet := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if patch.Name != nil {
+		addSet("name", *patch.Name)
+	}
+	if patch.YearsOfExperience != nil {
+		addSet("years_of_experience", *patch.YearsOfExperience)
+	}
+	if patch.HireDate != nil {
+		addSet("hire_date", nullString(*patch.HireDate))
+	}
+	if patch.PriorExperience != nil {
+		addSet("prior_experience", *patch.PriorExperience)
+	}
+	if patch.Breed != nil {
+		addSet("breed", *patch.Breed)
+	}
+	if patch.BreedDetails != nil {
+		addSet("breed_id", nullString(patch.BreedDetails.ID))
+		addSet("breed_origin", nullString(patch.BreedDetails.Origin))
+		addSet("breed_temperament", nullString(patch.BreedDetails.Temperament))
+		addSet("breed_life_span", nullString(patch.BreedDetails.LifeSpan))
+	}
+	if patch.BreedVerification != nil {
+		addSet("breed_verification", *patch.BreedVerification)
+	}
+	sets = append(sets, "updated_at = CURRENT_TIMESTAMP")
+
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// salary goes through the ledger so the change is recorded in the same transaction
+	if patch.Salary != nil {
+		if _, err := applySalaryChange(tx, catID, *patch.Salary, "", "profile update"); err != nil {
+			return nil, err
+		}
+	}
+
+	args = append(args, catID)
+	query := fmt.Sprintf(`UPDATE spy_cats SET %s WHERE id = $%d RETURNING %s`,
+		strings.Join(sets, ", "), len(args), catProfileColumns)
+
+	cat, err := scanCatProfile(tx.QueryRow(query, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return cat, tx.Commit()
+}
+
+func (cd *CatDatabase) SelectWithoutBreedDetails() ([]models.Cat, error) {
+	var cats []models.Cat
+
+	rows, err := cd.Connection.Query(`SELECT id, name, breed FROM spy_cats WHERE breed_id IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cat models.Cat
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.Breed); err != nil {
+			return nil, err
+		}
+		cats = append(cats, cat)
+	}
+
+	return cats, rows.Err()
+}
+
+func (cd *CatDatabase) UpdateBreed(catID int, breed models.Breed) error {
+	query := `UPDATE spy_cats 
+              SET breed = $1, breed_id = $2, breed_origin = $3, breed_temperament = $4, breed_life_span = $5,
+                  breed_verification = 'verified', updated_at = CURRENT_TIMESTAMP
+              WHERE id = $6`
+	result, err := cd.Connection.Exec(query, breed.Name, nullString(breed.ID), nullString(breed.Origin),
+		nullString(breed.Temperament), nullString(breed.LifeSpan), catID)
 	if err != nil {
 		return err
 	}
@@ -97,8 +404,158 @@ func (cd *CatDatabase) Update(catID int, salary float64) error {
 	return nil
 }
 
-func (cd *CatDatabase) Delete(catID int) error {
-	query := `DELETE FROM spy_cats WHERE id = $1`
+// Delete archives the cat, its mission history stays intact. Active missions are handed to
+// deletion.ReplacementCatID or left without a cat depending on the mode, all in one transaction.
+// The IDs of the active missions are returned, with ErrCatHasActiveMissions when the mode refuses them
+func (cd *CatDatabase) Delete(catID int, deletion models.CatDeletion) ([]int, error) {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	err = tx.QueryRow(`SELECT deleted_at IS NOT NULL FROM spy_cats WHERE id = $1 FOR UPDATE`, catID).Scan(&archived)
+	if err != nil {
+		return nil, err
+	}
+	if archived {
+		return nil, sql.ErrNoRows
+	}
+
+	missionIDs, err := selectActiveMissionIDs(tx, catID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missionIDs) > 0 {
+		switch deletion.Mode {
+		case models.CatDeletionReassign:
+			// a cat takes one active mission at a time, older rows may still hold more for the archived cat
+			if len(missionIDs) > 1 {
+				return missionIDs, fmt.Errorf("%w: it can take only one of the %d active missions, use ?mode=unassign", ErrReplacementCatUnavailable, len(missionIDs))
+			}
+			if err := lockAvailableCat(tx, deletion.ReplacementCatID, ErrReplacementCatUnavailable); err != nil {
+				return missionIDs, err
+			}
+			_, err = tx.Exec(`UPDATE missions SET cat_id = $1, updated_at = CURRENT_TIMESTAMP, assigned_at = CURRENT_TIMESTAMP
+				WHERE id = ANY($2)`,
+				deletion.ReplacementCatID, pq.Array(missionIDs))
+			if err == nil {
+				err = recordOverride(tx, missionIDs[0], deletion.Override)
+			}
+			if err == nil {
+				err = recordAssignments(tx, missionIDs, deletion.ReplacementCatID, catArchivedReason)
+			}
+		case models.CatDeletionUnassign:
+			// every active state may go back to unassigned, see assignmentTransitions in the service
+			_, err = tx.Exec(`UPDATE missions SET cat_id = NULL, status = 'unassigned', updated_at = CURRENT_TIMESTAMP, assigned_at = NULL,
+				override_cat_id = NULL, override_reason = NULL, override_unmet = NULL, overridden_at = NULL
+				WHERE id = ANY($1)`,
+				pq.Array(missionIDs))
+			if err == nil {
+				err = recordAssignments(tx, missionIDs, 0, catArchivedReason)
+			}
+		default:
+			return missionIDs, ErrCatHasActiveMissions
+		}
+		if err != nil {
+			return missionIDs, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE spy_cats SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, catID)
+	if err != nil {
+		return missionIDs, err
+	}
+
+	return missionIDs, tx.Commit()
+}
+
+// catArchivedReason is recorded in the assignment history of the missions a deleted cat leaves
+const catArchivedReason = "cat archived"
+
+// SelectActiveMissions returns the ID, status and requirements of the cat's active missions
+func (cd *CatDatabase) SelectActiveMissions(catID int) ([]models.Mission, error) {
+	rows, err := cd.Connection.Query(`
+		SELECT id, status, min_experience, allowed_breeds, max_salary, max_salary_currency
+		FROM missions
+		WHERE cat_id = $1 AND `+activeMissionCondition+`
+		ORDER BY id`, catID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missions []models.Mission
+	for rows.Next() {
+		mission := models.Mission{CatID: catID}
+		var minExperience sql.NullInt64
+		var allowedBreeds pq.StringArray
+		var maxSalary moneyDest
+		if err := rows.Scan(&mission.ID, &mission.Status, &minExperience, &allowedBreeds, &maxSalary.amount, &maxSalary.currency); err != nil {
+			return nil, err
+		}
+		if mission.Requirements, err = scanRequirements(minExperience, allowedBreeds, maxSalary); err != nil {
+			return nil, err
+		}
+		missions = append(missions, mission)
+	}
+
+	return missions, rows.Err()
+}
+
+func selectActiveMissionIDs(tx *sql.Tx, catID int) ([]int, error) {
+	var ids []int
+
+	rows, err := tx.Query(`SELECT id FROM missions WHERE cat_id = $1 AND `+activeMissionCondition+` ORDER BY id FOR UPDATE`, catID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// lockAvailableCat locks a cat about to be put on a mission so it cannot be archived or sent elsewhere
+// mid-transaction. Every transaction assigning a cat goes through it, so two of them for the same cat run one
+// after the other and the second sees the first one's mission. A missing, archived or busy cat is reported
+// wrapped in unavailable
+func lockAvailableCat(tx *sql.Tx, catID int, unavailable error) error {
+	var archived bool
+	err := tx.QueryRow(`SELECT deleted_at IS NOT NULL FROM spy_cats WHERE id = $1 FOR UPDATE`, catID).Scan(&archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: there is no cat with ID %d", unavailable, catID)
+	} else if err != nil {
+		return err
+	}
+	if archived {
+		return fmt.Errorf("%w: cat %d is archived", unavailable, catID)
+	}
+
+	var busy bool
+	err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM missions WHERE cat_id = $1 AND `+activeMissionCondition+`)`, catID).Scan(&busy)
+	if err != nil {
+		return err
+	}
+	if busy {
+		return fmt.Errorf("%w: cat %d is already assigned to an active mission", unavailable, catID)
+	}
+
+	return nil
+}
+
+func (cd *CatDatabase) Restore(catID int) error {
+	query := `UPDATE spy_cats SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND deleted_at IS NOT NULL`
 	result, err := cd.Connection.Exec(query, catID)
 	if err != nil {
 		return err
@@ -115,3 +572,139 @@ func (cd *CatDatabase) Delete(catID int) error {
 
 	return nil
 }
+
+// SelectAvailability reports the cat's active mission and when it was assigned, or for a free cat
+// when its last mission ended (its creation time if it never had one)
+func (cd *CatDatabase) SelectAvailability(catID int) (*models.CatAvailability, error) {
+	availability := models.CatAvailability{CatID: catID}
+	var missionID sql.NullInt64
+	var missionStatus sql.NullString
+	var since time.Time
+
+	err := cd.Connection.QueryRow(`
+		SELECT active.id, active.status,
+		       COALESCE(active.assigned_at, active.created_at,
+		                (SELECT MAX(m.updated_at) FROM missions m WHERE m.cat_id = c.id), c.created_at)
+		FROM spy_cats c
+		LEFT JOIN LATERAL (
+			SELECT m.id, m.status, m.assigned_at, m.created_at FROM missions m
+			WHERE m.cat_id = c.id AND m.`+activeMissionCondition+`
+			ORDER BY m.assigned_at DESC NULLS LAST, m.id DESC
+			LIMIT 1
+		) active ON TRUE
+		WHERE c.id = $1`, catID).Scan(&missionID, &missionStatus, &since)
+	if err != nil {
+		return nil, err
+	}
+
+	availability.Available = !missionID.Valid
+	if missionID.Valid {
+		id := int(missionID.Int64)
+		availability.MissionID = &id
+		availability.MissionStatus = models.MissionStatus(missionStatus.String)
+	}
+	availability.Since = since.Format("15:04:05 02:01:06")
+
+	return &availability, nil
+}
+
+func (cd *CatDatabase) SelectByBreedVerification(status models.BreedVerification) ([]models.Cat, error) {
+	var cats []models.Cat
+
+	rows, err := cd.Connection.Query(`SELECT id, name, breed FROM spy_cats WHERE breed_verification = $1 ORDER BY id`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cat models.Cat
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.Breed); err != nil {
+			return nil, err
+		}
+		cat.BreedVerification = status
+		cats = append(cats, cat)
+	}
+
+	return cats, rows.Err()
+}
+
+func (cd *CatDatabase) SetBreedVerification(catID int, status models.BreedVerification) error {
+	query := `UPDATE spy_cats SET breed_verification = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := cd.Connection.Exec(query, status, catID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// duplicateNameSimilarity is the trigram similarity from which a cat of the same breed counts as a near-duplicate
+const duplicateNameSimilarity = 0.6
+
+// scoredRow scans catProfileColumns followed by a score column
+type scoredRow struct {
+	rowScanner
+	score *float64
+}
+
+func (r scoredRow) Scan(dest ...interface{}) error {
+	return r.rowScanner.Scan(append(dest, r.score)...)
+}
+
+func (cd *CatDatabase) selectMatches(query string, args ...interface{}) ([]models.CatMatch, error) {
+	rows, err := cd.Connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []models.CatMatch
+	for rows.Next() {
+		var match models.CatMatch
+		cat, err := scanCatProfile(scoredRow{rows, &match.Score})
+		if err != nil {
+			return nil, err
+		}
+		match.Cat = *cat
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
+
+// Search finds active cats whose name or breed contains the query or resembles a word of it,
+// best matches first
+func (cd *CatDatabase) Search(query string, limit int) ([]models.CatMatch, error) {
+	return cd.selectMatches(`
+		SELECT `+catProfileColumns+`, score FROM (
+			SELECT *, GREATEST(word_similarity($1, name), word_similarity($1, breed),
+			                   CASE WHEN strpos(lower(name), lower($1)) > 0 OR strpos(lower(breed), lower($1)) > 0
+			                        THEN 1 ELSE 0 END) AS score
+			FROM spy_cats
+			WHERE deleted_at IS NULL
+			  AND ($1 <% name OR $1 <% breed OR strpos(lower(name), lower($1)) > 0 OR strpos(lower(breed), lower($1)) > 0)
+		) matches
+		ORDER BY score DESC, similarity($1, name) DESC, id
+		LIMIT $2
+	`, query, limit)
+}
+
+// SelectSimilar finds active cats of the same breed with a name close to the given one
+func (cd *CatDatabase) SelectSimilar(name, breed string) ([]models.CatMatch, error) {
+	return cd.selectMatches(`
+		SELECT `+catProfileColumns+`, similarity(name, $1) AS score
+		FROM spy_cats
+		WHERE deleted_at IS NULL AND lower(breed) = lower($2) AND similarity(name, $1) >= $3
+		ORDER BY score DESC, id
+	`, name, breed, duplicateNameSimilarity)
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"spyCat/database/models"
+	"strings"
+	"testing"
+)
+
+func TestCatFilterClauseSalaryBoundsMatchTheirCurrency(t *testing.T) {
+	minSalary := models.Money{Cents: 100000, Currency: "USD"}
+	maxSalary := models.Money{Cents: 500000, Currency: "USD"}
+
+	where, args := catFilterClause(models.CatFilter{MinSalary: &minSalary, MaxSalary: &maxSalary})
+
+	for _, cond := range []string{"salary_currency = $1", "salary >= $2", "salary_currency = $3", "salary <= $4"} {
+		if !strings.Contains(where, cond) {
+			t.Errorf("expected %q in %q", cond, where)
+		}
+	}
+	want := []interface{}{"USD", "1000.00", "USD", "5000.00"}
+	if len(args) != len(want) {
+		t.Fatalf("got args %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Fatalf("got args %v, want %v", args, want)
+		}
+	}
+}
+
+func TestCatFilterClauseSkillProficiency(t *testing.T) {
+	four := 4
+	tests := []struct {
+		name           string
+		minProficiency *int
+		want           int
+	}{
+		{"any proficiency by default", nil, models.MinProficiency},
+		{"given minimum", &four, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, args := catFilterClause(models.CatFilter{Skills: []string{" lockpicking"}, MinProficiency: tt.minProficiency})
+			if len(args) != 2 || args[0] != "lockpicking" || args[1] != tt.want {
+				t.Fatalf("got args %v, want [lockpicking %d]", args, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"database/sql"
+	"spyCat/database/models"
+	"time"
+)
+
+const rankStatsColumns = `id, ` + catExperienceExpr + `,
+	(SELECT COUNT(*) FROM missions m WHERE m.cat_id = spy_cats.id AND m.status = 'completed'), COALESCE(rank, '')`
+
+func scanRankStats(row rowScanner) (*models.RankStats, error) {
+	var stats models.RankStats
+	if err := row.Scan(&stats.CatID, &stats.YearsOfExperience, &stats.CompletedMissions, &stats.Rank); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// SelectRankStats returns what the cat's rank is computed from
+func (cd *CatDatabase) SelectRankStats(catID int) (*models.RankStats, error) {
+	return scanRankStats(cd.Connection.QueryRow(`SELECT `+rankStatsColumns+` FROM spy_cats WHERE id = $1`, catID))
+}
+
+// SelectAllRankStats returns the rank stats of every active cat
+func (cd *CatDatabase) SelectAllRankStats() ([]models.RankStats, error) {
+	rows, err := cd.Connection.Query(`SELECT ` + rankStatsColumns + ` FROM spy_cats WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []models.RankStats
+	for rows.Next() {
+		stats, err := scanRankStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, *stats)
+	}
+
+	return all, rows.Err()
+}
+
+// RecordRankChange moves the cat from its recorded rank to the new one and writes the promotion history.
+// It returns false without writing anything when the recorded rank is no longer stats.Rank
+func (cd *CatDatabase) RecordRankChange(stats models.RankStats, toRank string) (bool, error) {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE spy_cats SET rank = $1 WHERE id = $2 AND COALESCE(rank, '') = $3`,
+		toRank, stats.CatID, stats.Rank)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO cat_promotions (cat_id, from_rank, to_rank, years_of_experience, completed_missions)
+		VALUES ($1, $2, $3, $4, $5)
+	`, stats.CatID, nullString(stats.Rank), toRank, stats.YearsOfExperience, stats.CompletedMissions)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (cd *CatDatabase) SelectPromotions(catID int) ([]models.Promotion, error) {
+	rows, err := cd.Connection.Query(`
+		SELECT id, cat_id, from_rank, to_rank, years_of_experience, completed_missions, promoted_at
+		FROM cat_promotions
+		WHERE cat_id = $1
+		ORDER BY promoted_at, id
+	`, catID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		var promotion models.Promotion
+		var fromRank sql.NullString
+		var promotedAt time.Time
+		err := rows.Scan(&promotion.ID, &promotion.CatID, &fromRank, &promotion.ToRank,
+			&promotion.YearsOfExperience, &promotion.CompletedMissions, &promotedAt)
+		if err != nil {
+			return nil, err
+		}
+		promotion.FromRank = fromRank.String
+		promotion.PromotedAt = promotedAt.Format("15:04:05 02:01:06")
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"spyCat/database/models"
+	"time"
+)
+
+const salaryChangeColumns = `id, cat_id, old_salary, old_currency, new_salary, currency, effective_date, reason, status,
+	reviewed_by, review_note, reviewed_at, applied_at, created_at`
+
+func scanSalaryChange(row rowScanner) (*models.SalaryChange, error) {
+	var change models.SalaryChange
+	var oldSalary, newSalary moneyDest
+	var effectiveDate, createdAt time.Time
+	var reviewedBy sql.NullString
+	var reviewedAt, appliedAt sql.NullTime
+
+	err := row.Scan(&change.ID, &change.CatID, &oldSalary.amount, &oldSalary.currency, &newSalary.amount, &newSalary.currency,
+		&effectiveDate, &change.Reason, &change.Status, &reviewedBy, &change.ReviewNote, &reviewedAt, &appliedAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	change.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		change.ReviewedAt = reviewedAt.Time.Format("15:04:05 02:01:06")
+	}
+
+	if change.OldSalary, err = oldSalary.money(); err != nil {
+		return nil, err
+	}
+	if change.NewSalary, err = newSalary.value(); err != nil {
+		return nil, err
+	}
+	change.EffectiveDate = effectiveDate.Format(models.DateLayout)
+	if appliedAt.Valid {
+		change.AppliedAt = appliedAt.Time.Format("15:04:05 02:01:06")
+	}
+	change.CreatedAt = createdAt.Format("15:04:05 02:01:06")
+
+	return &change, nil
+}
+
+// Update sets the cat's salary and records the change in the ledger in one transaction
+func (cd *CatDatabase) Update(change *models.SalaryChange) error {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	applied, err := applySalaryChange(tx, change.CatID, change.NewSalary, change.EffectiveDate, change.Reason)
+	if err != nil {
+		return err
+	}
+	*change = *applied
+
+	return tx.Commit()
+}
+
+// applySalaryChange locks the cat row so concurrent changes record the right old salary
+func applySalaryChange(tx *sql.Tx, catID int, salary models.Money, effectiveDate, reason string) (*models.SalaryChange, error) {
+	var oldSalary, oldCurrency string
+	err := tx.QueryRow(`SELECT salary, salary_currency FROM spy_cats WHERE id = $1 FOR UPDATE`, catID).Scan(&oldSalary, &oldCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE spy_cats SET salary = $1, salary_currency = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`,
+		salary.Decimal(), salary.Currency, catID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSalaryChange(tx.QueryRow(`
+		INSERT INTO cat_salary_changes (cat_id, old_salary, old_currency, new_salary, currency, effective_date, reason, applied_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, '')::DATE, CURRENT_DATE), $7, CURRENT_TIMESTAMP)
+		RETURNING `+salaryChangeColumns, catID, oldSalary, oldCurrency, salary.Decimal(), salary.Currency, effectiveDate, reason))
+}
+
+// ScheduleSalaryChange records a change to apply later, either on its effective date or once it is approved
+// when its Status is pending
+func (cd *CatDatabase) ScheduleSalaryChange(change *models.SalaryChange) error {
+	status := change.Status
+	if status == "" {
+		status = models.SalaryChangeApproved
+	}
+
+	scheduled, err := scanSalaryChange(cd.Connection.QueryRow(`
+		INSERT INTO cat_salary_changes (cat_id, new_salary, currency, effective_date, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+salaryChangeColumns, change.CatID, change.NewSalary.Decimal(), change.NewSalary.Currency, change.EffectiveDate, change.Reason, status))
+	if err != nil {
+		return err
+	}
+
+	*change = *scheduled
+	return nil
+}
+
+func (cd *CatDatabase) SelectSalaryHistory(catID int) ([]models.SalaryChange, error) {
+	changes := []models.SalaryChange{}
+
+	rows, err := cd.Connection.Query(`SELECT `+salaryChangeColumns+` FROM cat_salary_changes
+		WHERE cat_id = $1 ORDER BY effective_date, id`, catID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		change, err := scanSalaryChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, rows.Err()
+}
+
+// ApplyDueSalaryChanges applies every scheduled change whose effective date has come, oldest first.
+// Each change gets its own transaction and SKIP LOCKED keeps concurrent runs from applying one twice
+func (cd *CatDatabase) ApplyDueSalaryChanges() (int, error) {
+	var applied int
+	for {
+		ok, err := cd.applyNextDueSalaryChange()
+		if err != nil {
+			return applied, err
+		}
+		if !ok {
+			return applied, nil
+		}
+		applied++
+	}
+}
+
+func (cd *CatDatabase) applyNextDueSalaryChange() (bool, error) {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id, catID int
+	err = tx.QueryRow(`
+		SELECT id, cat_id FROM cat_salary_changes
+		WHERE applied_at IS NULL AND status = 'approved' AND effective_date <= CURRENT_DATE
+		ORDER BY effective_date, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`).Scan(&id, &catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if err := applyLedgerEntry(tx, id, catID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// applyLedgerEntry sets the cat's salary from a ledger entry the caller has locked and marks it applied
+func applyLedgerEntry(tx *sql.Tx, id, catID int) error {
+	var oldSalary, oldCurrency string
+	err := tx.QueryRow(`SELECT salary, salary_currency FROM spy_cats WHERE id = $1 FOR UPDATE`, catID).Scan(&oldSalary, &oldCurrency)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE spy_cats SET salary = c.new_salary, salary_currency = c.currency, updated_at = CURRENT_TIMESTAMP
+		FROM cat_salary_changes c
+		WHERE c.id = $1 AND spy_cats.id = c.cat_id`, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE cat_salary_changes SET old_salary = $1, old_currency = $2, applied_at = CURRENT_TIMESTAMP WHERE id = $3`,
+		oldSalary, oldCurrency, id)
+	return err
+}
+
+func (cd *CatDatabase) SelectSalaryChange(id int) (*models.SalaryChange, error) {
+	return scanSalaryChange(cd.Connection.QueryRow(`SELECT `+salaryChangeColumns+` FROM cat_salary_changes WHERE id = $1`, id))
+}
+
+// SelectSalaryChangesByStatus lists the changes awaiting or past review, oldest first
+func (cd *CatDatabase) SelectSalaryChangesByStatus(status models.SalaryChangeStatus) ([]models.SalaryChange, error) {
+	changes := []models.SalaryChange{}
+
+	rows, err := cd.Connection.Query(`SELECT `+salaryChangeColumns+` FROM cat_salary_changes
+		WHERE status = $1 ORDER BY created_at, id`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		change, err := scanSalaryChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, rows.Err()
+}
+
+// ReviewSalaryChange approves or rejects a pending change, sql.ErrNoRows means it is no longer pending.
+// An approved change whose effective date has come is applied in the same transaction, later ones are left
+// to ApplyDueSalaryChanges
+func (cd *CatDatabase) ReviewSalaryChange(id int, status models.SalaryChangeStatus, review models.SalaryReview) (*models.SalaryChange, error) {
+	tx, err := cd.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var catID int
+	var due bool
+	err = tx.QueryRow(`
+		UPDATE cat_salary_changes
+		SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = 'pending'
+		RETURNING cat_id, status = 'approved' AND applied_at IS NULL AND effective_date <= CURRENT_DATE`,
+		status, review.ReviewedBy, review.Note, id).Scan(&catID, &due)
+	if err != nil {
+		return nil, err
+	}
+
+	if due {
+		if err := applyLedgerEntry(tx, id, catID); err != nil {
+			return nil, err
+		}
+	}
+
+	change, err := scanSalaryChange(tx.QueryRow(`SELECT `+salaryChangeColumns+` FROM cat_salary_changes WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return change, tx.Commit()
+}
@@ -0,0 +1,5 @@
+ALTER TABLE spy_cats
+      DROP COLUMN breed_id,
+      DROP COLUMN breed_origin,
+      DROP COLUMN breed_temperament,
+      DROP COLUMN breed_life_span;
@@ -0,0 +1,7 @@
+-- Canonical breed data copied from the breed catalog,
+-- existing rows are filled in by `go run ./cmd/backfill_breeds`
+ALTER TABLE spy_cats
+      ADD COLUMN breed_id VARCHAR(10),
+      ADD COLUMN breed_origin VARCHAR(100),
+      ADD COLUMN breed_temperament TEXT,
+      ADD COLUMN breed_life_span VARCHAR(20);
@@ -0,0 +1,5 @@
+DROP INDEX spy_cats_breed_verification_pending_idx;
+
+ALTER TABLE spy_cats DROP COLUMN breed_verification;
+
+DROP TYPE breed_verification;
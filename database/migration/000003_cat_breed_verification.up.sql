@@ -0,0 +1,7 @@
+CREATE TYPE breed_verification AS ENUM ('pending', 'verified', 'rejected');
+
+-- cats created while the breed source was unreachable stay pending until the verifier re-checks them
+ALTER TABLE spy_cats
+      ADD COLUMN breed_verification breed_verification NOT NULL DEFAULT 'verified';
+
+CREATE INDEX spy_cats_breed_verification_pending_idx ON spy_cats (id) WHERE breed_verification = 'pending';
@@ -0,0 +1,14 @@
+-- Payroll ledger, rows with applied_at NULL are scheduled raises waiting for their effective_date
+CREATE TABLE cat_salary_changes (
+      id SERIAL PRIMARY KEY,
+      cat_id INTEGER NOT NULL REFERENCES spy_cats(id) ON DELETE CASCADE,
+      old_salary DECIMAL(10, 2),
+      new_salary DECIMAL(10, 2) NOT NULL,
+      effective_date DATE NOT NULL DEFAULT CURRENT_DATE,
+      reason TEXT NOT NULL DEFAULT '',
+      applied_at TIMESTAMP WITH TIME ZONE,
+      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX cat_salary_changes_cat_id_idx ON cat_salary_changes (cat_id, effective_date);
+CREATE INDEX cat_salary_changes_scheduled_idx ON cat_salary_changes (effective_date) WHERE applied_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE cat_salary_changes
+      DROP COLUMN old_currency,
+      DROP COLUMN currency;
+
+ALTER TABLE spy_cats DROP COLUMN salary_currency;
@@ -0,0 +1,9 @@
+-- Salaries are fixed-point amounts with an explicit ISO 4217 currency code
+ALTER TABLE spy_cats
+      ADD COLUMN salary_currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE cat_salary_changes
+      ADD COLUMN old_currency CHAR(3),
+      ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+UPDATE cat_salary_changes SET old_currency = 'USD' WHERE old_salary IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX spy_cats_active_idx;
+
+ALTER TABLE spy_cats DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- Deleting a cat archives it so its missions keep pointing at a real row
+ALTER TABLE spy_cats ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX spy_cats_active_idx ON spy_cats (id) WHERE deleted_at IS NULL;
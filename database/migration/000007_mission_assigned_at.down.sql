@@ -0,0 +1,3 @@
+DROP INDEX missions_cat_id_status_idx;
+
+ALTER TABLE missions DROP COLUMN assigned_at;
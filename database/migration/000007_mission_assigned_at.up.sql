@@ -0,0 +1,6 @@
+-- When the current cat was put on the mission, used to report cat availability
+ALTER TABLE missions ADD COLUMN assigned_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE missions SET assigned_at = created_at WHERE cat_id IS NOT NULL;
+
+CREATE INDEX missions_cat_id_status_idx ON missions (cat_id, status);
@@ -0,0 +1,3 @@
+DROP TABLE cat_skills;
+
+DROP TABLE skills;
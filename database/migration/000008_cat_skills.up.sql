@@ -0,0 +1,28 @@
+-- Skills catalog
+CREATE TABLE skills (
+      id SERIAL PRIMARY KEY,
+      name VARCHAR(100) NOT NULL UNIQUE,
+      description TEXT NOT NULL DEFAULT '',
+      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Skills of each cat, proficiency goes from 1 (novice) to 5 (master)
+CREATE TABLE cat_skills (
+      cat_id INTEGER NOT NULL REFERENCES spy_cats(id) ON DELETE CASCADE,
+      skill_id INTEGER NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+      proficiency SMALLINT NOT NULL CHECK (proficiency BETWEEN 1 AND 5),
+      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+      updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+      PRIMARY KEY (cat_id, skill_id)
+);
+
+CREATE INDEX cat_skills_skill_id_idx ON cat_skills (skill_id, proficiency);
+
+INSERT INTO skills (name, description) VALUES
+      ('infiltration', 'Getting into guarded places unnoticed'),
+      ('surveillance', 'Watching targets for long periods without being spotted'),
+      ('interrogation', 'Getting information out of reluctant sources'),
+      ('disguise', 'Passing as an ordinary house cat'),
+      ('lockpicking', 'Opening doors, windows and cat flaps'),
+      ('hacking', 'Walking across keyboards with purpose'),
+      ('extraction', 'Getting assets and fellow agents out safely');
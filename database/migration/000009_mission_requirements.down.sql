@@ -0,0 +1,9 @@
+ALTER TABLE missions
+      DROP COLUMN min_experience,
+      DROP COLUMN allowed_breeds,
+      DROP COLUMN max_salary,
+      DROP COLUMN max_salary_currency,
+      DROP COLUMN override_cat_id,
+      DROP COLUMN override_reason,
+      DROP COLUMN override_unmet,
+      DROP COLUMN overridden_at;
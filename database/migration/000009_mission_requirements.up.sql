@@ -0,0 +1,13 @@
+-- What a cat needs to be sent on the mission, NULL means no requirement
+ALTER TABLE missions
+      ADD COLUMN min_experience INTEGER,
+      ADD COLUMN allowed_breeds TEXT[],
+      ADD COLUMN max_salary DECIMAL(10, 2),
+      ADD COLUMN max_salary_currency CHAR(3);
+
+-- Set when a cat was assigned even though it did not meet the requirements
+ALTER TABLE missions
+      ADD COLUMN override_cat_id INTEGER REFERENCES spy_cats(id),
+      ADD COLUMN override_reason TEXT,
+      ADD COLUMN override_unmet TEXT[],
+      ADD COLUMN overridden_at TIMESTAMP WITH TIME ZONE;
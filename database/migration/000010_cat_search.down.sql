@@ -0,0 +1,3 @@
+DROP INDEX spy_cats_breed_trgm_idx;
+
+DROP INDEX spy_cats_name_trgm_idx;
@@ -0,0 +1,5 @@
+-- Trigram matching for the fuzzy cat search and duplicate detection
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX spy_cats_name_trgm_idx ON spy_cats USING GIN (name gin_trgm_ops);
+CREATE INDEX spy_cats_breed_trgm_idx ON spy_cats USING GIN (breed gin_trgm_ops);
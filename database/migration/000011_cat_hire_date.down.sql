@@ -0,0 +1,3 @@
+ALTER TABLE spy_cats
+    DROP COLUMN prior_experience,
+    DROP COLUMN hire_date;
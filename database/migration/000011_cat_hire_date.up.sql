@@ -0,0 +1,5 @@
+-- Experience is derived from the hire date when there is one: prior_experience plus the full years since hire_date.
+-- years_of_experience stays the value for cats without a hire date
+ALTER TABLE spy_cats
+    ADD COLUMN hire_date DATE,
+    ADD COLUMN prior_experience INTEGER NOT NULL DEFAULT 0 CHECK (prior_experience >= 0);
@@ -0,0 +1,3 @@
+DROP TABLE cat_promotions;
+
+ALTER TABLE spy_cats DROP COLUMN rank;
@@ -0,0 +1,15 @@
+-- Rank last recorded by the promotion job, ranks are configured so they are plain names
+ALTER TABLE spy_cats ADD COLUMN rank VARCHAR(50);
+
+-- Every rank change, from_rank is NULL for the cat's first rank
+CREATE TABLE cat_promotions (
+      id SERIAL PRIMARY KEY,
+      cat_id INTEGER NOT NULL REFERENCES spy_cats(id) ON DELETE CASCADE,
+      from_rank VARCHAR(50),
+      to_rank VARCHAR(50) NOT NULL,
+      years_of_experience INTEGER NOT NULL,
+      completed_missions INTEGER NOT NULL,
+      promoted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX cat_promotions_cat_id_idx ON cat_promotions (cat_id, promoted_at);
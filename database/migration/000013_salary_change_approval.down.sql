@@ -0,0 +1,7 @@
+DROP INDEX cat_salary_changes_pending_idx;
+
+ALTER TABLE cat_salary_changes
+      DROP COLUMN reviewed_at,
+      DROP COLUMN review_note,
+      DROP COLUMN reviewed_by,
+      DROP COLUMN status;
@@ -0,0 +1,9 @@
+-- Raises above the approval threshold wait as pending until a supervisor approves or rejects them,
+-- only approved changes are applied
+ALTER TABLE cat_salary_changes
+      ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'approved' CHECK (status IN ('pending', 'approved', 'rejected')),
+      ADD COLUMN reviewed_by TEXT,
+      ADD COLUMN review_note TEXT NOT NULL DEFAULT '',
+      ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX cat_salary_changes_pending_idx ON cat_salary_changes (created_at) WHERE status = 'pending';
@@ -0,0 +1,10 @@
+-- Enum values cannot be dropped, rebuild the type. Aborted missions are folded into completed
+UPDATE missions SET status = 'in_progress' WHERE status IN ('draft', 'assigned', 'on_hold');
+UPDATE missions SET status = 'completed' WHERE status = 'aborted';
+
+ALTER TABLE missions ALTER COLUMN status DROP DEFAULT;
+ALTER TYPE mission_status RENAME TO mission_status_old;
+CREATE TYPE mission_status AS ENUM ('in_progress', 'completed');
+ALTER TABLE missions ALTER COLUMN status TYPE mission_status USING status::text::mission_status;
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'in_progress';
+DROP TYPE mission_status_old;
@@ -0,0 +1,6 @@
+-- Mission lifecycle: draft -> assigned -> in_progress <-> on_hold -> completed, any open state can be aborted.
+-- New enum values cannot be used in the transaction that adds them, existing rows are moved in 000015
+ALTER TYPE mission_status ADD VALUE IF NOT EXISTS 'draft' BEFORE 'in_progress';
+ALTER TYPE mission_status ADD VALUE IF NOT EXISTS 'assigned' BEFORE 'in_progress';
+ALTER TYPE mission_status ADD VALUE IF NOT EXISTS 'on_hold' AFTER 'in_progress';
+ALTER TYPE mission_status ADD VALUE IF NOT EXISTS 'aborted' AFTER 'on_hold';
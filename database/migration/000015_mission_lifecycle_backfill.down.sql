@@ -0,0 +1,3 @@
+UPDATE missions SET status = 'in_progress' WHERE status = 'draft';
+
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'in_progress';
@@ -0,0 +1,4 @@
+-- Missions are born as drafts, open missions left without a cat go back to draft
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'draft';
+
+UPDATE missions SET status = 'draft' WHERE cat_id IS NULL AND status = 'in_progress';
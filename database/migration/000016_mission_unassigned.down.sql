@@ -0,0 +1,9 @@
+-- Enum values cannot be dropped, rebuild the type. Unassigned missions go back to draft
+UPDATE missions SET status = 'draft' WHERE status = 'unassigned';
+
+ALTER TABLE missions ALTER COLUMN status DROP DEFAULT;
+ALTER TYPE mission_status RENAME TO mission_status_old;
+CREATE TYPE mission_status AS ENUM ('draft', 'assigned', 'in_progress', 'on_hold', 'aborted', 'completed');
+ALTER TABLE missions ALTER COLUMN status TYPE mission_status USING status::text::mission_status;
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'draft';
+DROP TYPE mission_status_old;
@@ -0,0 +1,3 @@
+-- Missions waiting for a cat sit in the unassigned dispatch queue, drafts stay out of it until published.
+-- New enum values cannot be used in the transaction that adds them, existing rows are moved in 000017
+ALTER TYPE mission_status ADD VALUE IF NOT EXISTS 'unassigned' AFTER 'draft';
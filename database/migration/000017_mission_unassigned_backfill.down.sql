@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_missions_unassigned;
+
+UPDATE missions SET status = 'draft' WHERE status = 'unassigned';
+
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'draft';
@@ -0,0 +1,6 @@
+-- Missions created without a cat join the dispatch queue, existing drafts had no way to be published so they join it too
+ALTER TABLE missions ALTER COLUMN status SET DEFAULT 'unassigned';
+
+UPDATE missions SET status = 'unassigned' WHERE cat_id IS NULL AND status = 'draft';
+
+CREATE INDEX IF NOT EXISTS idx_missions_unassigned ON missions (created_at) WHERE status = 'unassigned';
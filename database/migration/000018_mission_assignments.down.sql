@@ -0,0 +1 @@
+DROP TABLE mission_assignments;
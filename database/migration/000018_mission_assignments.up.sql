@@ -0,0 +1,19 @@
+-- Every cat a mission had, ended_at is NULL for the current one. It stays open when the mission is closed
+CREATE TABLE mission_assignments (
+      id SERIAL PRIMARY KEY,
+      mission_id INTEGER NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+      cat_id INTEGER NOT NULL REFERENCES spy_cats(id),
+      reason TEXT,
+      end_reason TEXT,
+      started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+      ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX mission_assignments_mission_id_idx ON mission_assignments (mission_id, started_at);
+CREATE UNIQUE INDEX mission_assignments_current_idx ON mission_assignments (mission_id) WHERE ended_at IS NULL;
+
+-- Earlier assignments were not kept, only the current cat of each mission is known
+INSERT INTO mission_assignments (mission_id, cat_id, started_at)
+SELECT id, cat_id, COALESCE(assigned_at, created_at)
+FROM missions
+WHERE cat_id IS NOT NULL;
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"spyCat/database/models"
+	"time"
+)
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// recordAssignments ends the current assignment of each mission and, unless catID is 0, starts one for catID.
+// The reason is kept on both the ended and the started assignments
+func recordAssignments(q execer, missionIDs []int, catID int, reason string) error {
+	now := time.Now()
+
+	_, err := q.Exec(`
+		UPDATE mission_assignments
+		SET ended_at = $1, end_reason = $2
+		WHERE mission_id = ANY($3) AND ended_at IS NULL
+	`, now, nullString(reason), pq.Array(missionIDs))
+	if err != nil || catID == 0 {
+		return err
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO mission_assignments (mission_id, cat_id, reason, started_at)
+		SELECT mission_id, $1, $2, $3 FROM unnest($4::int[]) AS mission_id
+	`, catID, nullString(reason), now, pq.Array(missionIDs))
+	return err
+}
+
+// UnassignCat takes the cat off an active mission and puts the mission back in the unassigned queue,
+// sql.ErrNoRows means the mission has no cat or is not active
+func (md *MissionDatabase) UnassignCat(missionID int, reason string) error {
+	tx, err := md.Connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE missions
+		SET cat_id = NULL, status = 'unassigned', updated_at = $1, assigned_at = NULL,
+		    override_cat_id = NULL, override_reason = NULL, override_unmet = NULL, overridden_at = NULL
+		WHERE id = $2 AND cat_id IS NOT NULL AND `+activeMissionCondition,
+		time.Now(), missionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := recordAssignments(tx, []int{missionID}, 0, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// briefingSeparator goes between a target's existing notes and a handoff briefing
+const briefingSeparator = "\n\n"
+
+// HandoffMission moves an active mission from one cat to another in one transaction: the mission keeps its state
+// and completed targets, the briefing is appended to the notes of the open targets and the previous cat's
+// assignment ends. ErrCatUnavailable means the new cat was taken meanwhile, sql.ErrNoRows that the mission is
+// no longer active with fromCatID
+func (md *MissionDatabase) HandoffMission(missionID, fromCatID, toCatID int, override *models.EligibilityOverride, reason, briefing string) error {
+	tx, err := md.Connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockAvailableCat(tx, toCatID, ErrCatUnavailable); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE missions
+		SET cat_id = $1, updated_at = $2, assigned_at = $2
+		WHERE id = $3 AND cat_id = $4 AND `+activeMissionCondition,
+		toCatID, now, missionID, fromCatID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+		UPDATE targets
+		SET notes = CONCAT_WS($1::text, NULLIF(notes, ''), $2::text), updated_at = $3
+		WHERE mission_id = $4 AND status <> 'completed'
+	`, briefingSeparator, briefing, now, missionID)
+	if err != nil {
+		return err
+	}
+
+	if err := recordOverride(tx, missionID, override); err != nil {
+		return err
+	}
+	if err := recordAssignments(tx, []int{missionID}, toCatID, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SelectAssignments returns the assignment history of a mission, oldest first
+func (md *MissionDatabase) SelectAssignments(missionID int) ([]models.MissionAssignment, error) {
+	rows, err := md.Connection.Query(`
+		SELECT id, mission_id, cat_id, COALESCE(reason, ''), COALESCE(end_reason, ''), started_at, ended_at
+		FROM mission_assignments
+		WHERE mission_id = $1
+		ORDER BY started_at, id
+	`, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []models.MissionAssignment{}
+	for rows.Next() {
+		var assignment models.MissionAssignment
+		var startedAt time.Time
+		var endedAt sql.NullTime
+		err := rows.Scan(&assignment.ID, &assignment.MissionID, &assignment.CatID, &assignment.Reason, &assignment.EndReason,
+			&startedAt, &endedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		assignment.StartedAt = startedAt.Format("15:04:05 02:01:06")
+		if endedAt.Valid {
+			assignment.EndedAt = endedAt.Time.Format("15:04:05 02:01:06")
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"errors"
+	"github.com/lib/pq"
 	"spyCat/database/models"
 	"time"
 )
@@ -11,17 +12,26 @@ type MissionDatabaseInterface interface {
 	CreateMission(mission *models.Mission) error
 	DeleteMission(id int) error
 	UpdateMission(mission *models.Mission) error
-	CompleteMission(id int) error
-	AssignCatToMission(missionID, catID int) error
-	ListMissions() (*[]models.Mission, error)
+	TransitionMission(id int, from, to models.MissionStatus) error
+	AssignCatToMission(missionID, fromCatID, catID int, override *models.EligibilityOverride, reason string) error
+	UnassignCat(missionID int, reason string) error
+	HandoffMission(missionID, fromCatID, toCatID int, override *models.EligibilityOverride, reason, briefing string) error
+	SelectAssignments(missionID int) ([]models.MissionAssignment, error)
+	ListMissions(filter models.MissionFilter) (*[]models.Mission, error)
 	GetMission(id int) (*models.Mission, error)
 	IsMissionAssignedToCat(missionID int) (bool, error)
 	IsMissionCompleted(missionID int) (bool, error)
 	IsCatAvailable(catID int) (bool, error)
 	IsMissionAssigned(missionID int) (bool, error)
 	DoesCatExist(catID int) (bool, error)
+	IsCatArchived(catID int) (bool, error)
+	GetCat(catID int) (*models.Cat, error)
+	SelectCandidates(missionID int, recentSince time.Time) ([]models.CandidateStats, error)
 }
 
+// activeMissionCondition matches missions that keep their cat busy
+const activeMissionCondition = `status IN ('assigned', 'in_progress', 'on_hold')`
+
 type MissionDatabase struct {
 	*Database
 }
@@ -37,11 +47,27 @@ func (md *MissionDatabase) CreateMission(mission *models.Mission) error {
 	}
 	defer tx.Rollback()
 
+	minExperience, allowedBreeds, maxSalary, maxSalaryCurrency := requirementArgs(mission.Requirements)
+	overrideCatID, overrideReason, overrideUnmet, overriddenAt := overrideArgs(mission.EligibilityOverride)
+
+	// a mission created without a cat has no cat_id and no assigned_at
+	var catID, assignedAt interface{}
+	if mission.CatID != 0 {
+		if err := lockAvailableCat(tx, mission.CatID, ErrCatUnavailable); err != nil {
+			return err
+		}
+		catID, assignedAt = mission.CatID, time.Now()
+	}
+
 	err = tx.QueryRow(`
-		INSERT INTO missions (cat_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO missions (cat_id, status, created_at, updated_at, assigned_at,
+		                      min_experience, allowed_breeds, max_salary, max_salary_currency,
+		                      override_cat_id, override_reason, override_unmet, overridden_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id
-	`, mission.CatID, mission.Status, time.Now(), time.Now()).Scan(&mission.ID)
+	`, catID, mission.Status, time.Now(), time.Now(), assignedAt,
+		minExperience, allowedBreeds, maxSalary, maxSalaryCurrency,
+		overrideCatID, overrideReason, overrideUnmet, overriddenAt).Scan(&mission.ID)
 	if err != nil {
 		return err
 	}
@@ -58,9 +84,41 @@ func (md *MissionDatabase) CreateMission(mission *models.Mission) error {
 		}
 	}
 
+	if mission.CatID != 0 {
+		if err := recordAssignments(tx, []int{mission.ID}, mission.CatID, ""); err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
+func requirementArgs(req *models.MissionRequirements) (interface{}, interface{}, interface{}, interface{}) {
+	if req.IsEmpty() {
+		return nil, nil, nil, nil
+	}
+
+	var minExperience, allowedBreeds, maxSalary, maxSalaryCurrency interface{}
+	if req.MinExperience != nil {
+		minExperience = *req.MinExperience
+	}
+	if len(req.AllowedBreeds) > 0 {
+		allowedBreeds = pq.Array(req.AllowedBreeds)
+	}
+	if req.MaxSalary != nil {
+		maxSalary, maxSalaryCurrency = req.MaxSalary.Decimal(), req.MaxSalary.Currency
+	}
+
+	return minExperience, allowedBreeds, maxSalary, maxSalaryCurrency
+}
+
+func overrideArgs(override *models.EligibilityOverride) (interface{}, interface{}, interface{}, interface{}) {
+	if override == nil {
+		return nil, nil, nil, nil
+	}
+	return override.CatID, override.Reason, pq.Array(override.UnmetRequirements), time.Now()
+}
+
 func (md *MissionDatabase) DeleteMission(id int) error {
 	result, err := md.Connection.Exec("DELETE FROM missions WHERE id = $1", id)
 	if err != nil {
@@ -80,40 +138,84 @@ func (md *MissionDatabase) DeleteMission(id int) error {
 }
 
 func (md *MissionDatabase) UpdateMission(mission *models.Mission) error {
-	result, err := md.Connection.Exec(`UPDATE missions
-	SET cat_id = $1, status = $2, updated_at = $3
-	WHERE id = $4`, mission.CatID, mission.Status, time.Now(), mission.ID)
+	tx, err := md.Connection.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	var currentCatID sql.NullInt64
+	err = tx.QueryRow(`SELECT cat_id FROM missions WHERE id = $1 FOR UPDATE`, mission.ID).Scan(&currentCatID)
 	if err != nil {
 		return err
 	}
-
-	if rowsAffected == 0 {
+	// the current cat is only replaced through AssignCatToMission, which records the reason
+	if currentCatID.Valid && int(currentCatID.Int64) != mission.CatID {
 		return sql.ErrNoRows
 	}
+	catChanged := !currentCatID.Valid
+	if catChanged {
+		if err := lockAvailableCat(tx, mission.CatID, ErrCatUnavailable); err != nil {
+			return err
+		}
+	}
 
-	return err
+	// the status only moves through TransitionMission, a mission waiting for a cat becomes assigned once it has one
+	_, err = tx.Exec(`UPDATE missions
+	SET cat_id = $1, updated_at = $2,
+	    status = CASE WHEN status IN ('draft', 'unassigned') THEN 'assigned' ELSE status END,
+	    assigned_at = CASE WHEN cat_id IS DISTINCT FROM $1 THEN $2 ELSE assigned_at END
+	WHERE id = $3`, mission.CatID, time.Now(), mission.ID)
+	if err != nil {
+		return err
+	}
+
+	if catChanged {
+		// UpdateMission only takes cats that meet the requirements, so no override applies to the new cat
+		if err := recordOverride(tx, mission.ID, nil); err != nil {
+			return err
+		}
+		if err := recordAssignments(tx, []int{mission.ID}, mission.CatID, ""); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (md *MissionDatabase) CompleteMission(id int) error {
-	_, err := md.Connection.Exec(`
+// TransitionMission moves the mission from one state to another, sql.ErrNoRows means it is no longer in the from state
+func (md *MissionDatabase) TransitionMission(id int, from, to models.MissionStatus) error {
+	result, err := md.Connection.Exec(`
 		UPDATE missions
-		SET status = 'completed', updated_at = $1
-		WHERE id = $2
-	`, time.Now(), id)
-	return err
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, to, time.Now(), id, from)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-func (md *MissionDatabase) ListMissions() (*[]models.Mission, error) {
+// ListMissions returns the newest missions first, except for the unassigned queue which is oldest first
+func (md *MissionDatabase) ListMissions(filter models.MissionFilter) (*[]models.Mission, error) {
+	where, order := "", "created_at DESC"
+	if filter.Unassigned {
+		where, order = "WHERE status = 'unassigned' AND cat_id IS NULL", "created_at ASC"
+	}
+
 	rows, err := md.Connection.Query(`
-		SELECT id, cat_id, status, created_at, updated_at
+		SELECT id, COALESCE(cat_id, 0), status, created_at, updated_at
 		FROM missions
-		ORDER BY created_at DESC
-	`)
+		` + where + `
+		ORDER BY ` + order)
 	if err != nil {
 		return nil, err
 	}
@@ -140,15 +242,36 @@ func (md *MissionDatabase) ListMissions() (*[]models.Mission, error) {
 func (md *MissionDatabase) GetMission(id int) (*models.Mission, error) {
 	var mission models.Mission
 	var createdAt, updatedAt time.Time
+	var minExperience, overrideCatID sql.NullInt64
+	var allowedBreeds, overrideUnmet pq.StringArray
+	var maxSalary moneyDest
+	var overrideReason sql.NullString
+	var overriddenAt sql.NullTime
 	err := md.Connection.QueryRow(`
-		SELECT id, cat_id, status, created_at, updated_at
+		SELECT id, COALESCE(cat_id, 0), status, created_at, updated_at,
+		       min_experience, allowed_breeds, max_salary, max_salary_currency,
+		       override_cat_id, override_reason, override_unmet, overridden_at
 		FROM missions
 		WHERE id = $1
-	`, id).Scan(&mission.ID, &mission.CatID, &mission.Status, &createdAt, &updatedAt)
+	`, id).Scan(&mission.ID, &mission.CatID, &mission.Status, &createdAt, &updatedAt,
+		&minExperience, &allowedBreeds, &maxSalary.amount, &maxSalary.currency,
+		&overrideCatID, &overrideReason, &overrideUnmet, &overriddenAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if mission.Requirements, err = scanRequirements(minExperience, allowedBreeds, maxSalary); err != nil {
+		return nil, err
+	}
+	if overriddenAt.Valid {
+		mission.EligibilityOverride = &models.EligibilityOverride{
+			CatID:             int(overrideCatID.Int64),
+			Reason:            overrideReason.String,
+			UnmetRequirements: overrideUnmet,
+			OverriddenAt:      overriddenAt.Time.Format("15:04:05 02:01:06"),
+		}
+	}
+
 	rows, err := md.Connection.Query(`
 		SELECT id, name, country, notes, status, created_at, updated_at
 		FROM targets
@@ -176,6 +299,23 @@ func (md *MissionDatabase) GetMission(id int) (*models.Mission, error) {
 	return &mission, nil
 }
 
+// scanRequirements builds the requirements from their columns, nil when the mission has none
+func scanRequirements(minExperience sql.NullInt64, allowedBreeds pq.StringArray, maxSalary moneyDest) (*models.MissionRequirements, error) {
+	requirements := models.MissionRequirements{AllowedBreeds: allowedBreeds}
+	if minExperience.Valid {
+		n := int(minExperience.Int64)
+		requirements.MinExperience = &n
+	}
+	var err error
+	if requirements.MaxSalary, err = maxSalary.money(); err != nil {
+		return nil, err
+	}
+	if requirements.IsEmpty() {
+		return nil, nil
+	}
+	return &requirements, nil
+}
+
 func (md *MissionDatabase) IsMissionAssignedToCat(missionID int) (bool, error) {
 	var catID sql.NullInt64
 	err := md.Connection.QueryRow("SELECT cat_id FROM missions WHERE id = $1", missionID).Scan(&catID)
@@ -200,18 +340,61 @@ func (md *MissionDatabase) IsMissionCompleted(missionID int) (bool, error) {
 	return status == "completed", nil
 }
 
-func (md *MissionDatabase) AssignCatToMission(missionID, catID int) error {
-	_, err := md.Connection.Exec(`
+// AssignCatToMission puts the cat on the mission in place of fromCatID, 0 when it has none, and records the
+// assignment with the reason and the eligibility override when there is one. A mission waiting for a cat becomes
+// assigned. ErrCatUnavailable means the cat was taken meanwhile, sql.ErrNoRows that the mission changed
+func (md *MissionDatabase) AssignCatToMission(missionID, fromCatID, catID int, override *models.EligibilityOverride, reason string) error {
+	tx, err := md.Connection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockAvailableCat(tx, catID, ErrCatUnavailable); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE missions
+		SET cat_id = $1, updated_at = $2, assigned_at = $2,
+		    status = CASE WHEN status IN ('draft', 'unassigned') THEN 'assigned' ELSE status END
+		WHERE id = $3 AND COALESCE(cat_id, 0) = $4 AND status NOT IN ('completed', 'aborted')
+	`, catID, time.Now(), missionID, fromCatID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := recordOverride(tx, missionID, override); err != nil {
+		return err
+	}
+	if err := recordAssignments(tx, []int{missionID}, catID, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// recordOverride stores the eligibility override of the mission's new cat, nil clears the previous cat's one
+func recordOverride(q execer, missionID int, override *models.EligibilityOverride) error {
+	overrideCatID, overrideReason, overrideUnmet, overriddenAt := overrideArgs(override)
+	_, err := q.Exec(`
 		UPDATE missions
-		SET cat_id = $1, updated_at = $2
-		WHERE id = $3
-	`, catID, time.Now(), missionID)
+		SET override_cat_id = $1, override_reason = $2, override_unmet = $3, overridden_at = $4
+		WHERE id = $5
+	`, overrideCatID, overrideReason, overrideUnmet, overriddenAt, missionID)
 	return err
 }
 
 func (md *MissionDatabase) IsCatAvailable(catID int) (bool, error) {
 	var count int
-	err := md.Connection.QueryRow("SELECT COUNT(*) FROM missions WHERE cat_id = $1 AND status = 'in_progress'", catID).Scan(&count)
+	err := md.Connection.QueryRow("SELECT COUNT(*) FROM missions WHERE cat_id = $1 AND "+activeMissionCondition, catID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -238,3 +421,73 @@ func (md *MissionDatabase) DoesCatExist(catID int) (bool, error) {
 	}
 	return exists, nil
 }
+
+func (md *MissionDatabase) IsCatArchived(catID int) (bool, error) {
+	var archived bool
+	err := md.Connection.QueryRow("SELECT deleted_at IS NOT NULL FROM spy_cats WHERE id = $1", catID).Scan(&archived)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return archived, nil
+}
+
+// GetCat loads what the eligibility checks need to know about a cat
+func (md *MissionDatabase) GetCat(catID int) (*models.Cat, error) {
+	var cat models.Cat
+	var salary moneyDest
+	err := md.Connection.QueryRow(`
+		SELECT id, name, `+catExperienceExpr+`, breed, salary, salary_currency
+		FROM spy_cats
+		WHERE id = $1
+	`, catID).Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &salary.amount, &salary.currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if cat.Salary, err = salary.value(); err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// SelectCandidates returns the free, non-archived cats with their track record: completed missions,
+// missions assigned since recentSince, and completed targets in the countries of this mission's targets
+func (md *MissionDatabase) SelectCandidates(missionID int, recentSince time.Time) ([]models.CandidateStats, error) {
+	rows, err := md.Connection.Query(`
+		SELECT c.id, c.name, `+catExperienceExpr+`, c.breed, c.salary, c.salary_currency,
+		       (SELECT COUNT(*) FROM missions m WHERE m.cat_id = c.id AND m.status = 'completed'),
+		       (SELECT COUNT(*) FROM missions m WHERE m.cat_id = c.id AND m.assigned_at >= $2),
+		       (SELECT COUNT(*) FROM targets t
+		        JOIN missions m ON m.id = t.mission_id
+		        WHERE m.cat_id = c.id AND m.id <> $1 AND t.status = 'completed'
+		          AND LOWER(t.country) IN (SELECT LOWER(country) FROM targets WHERE mission_id = $1))
+		FROM spy_cats c
+		WHERE c.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM missions m WHERE m.cat_id = c.id AND m.`+activeMissionCondition+`)
+		ORDER BY c.id
+	`, missionID, recentSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []models.CandidateStats
+	for rows.Next() {
+		var stats models.CandidateStats
+		var salary moneyDest
+		err := rows.Scan(&stats.Cat.ID, &stats.Cat.Name, &stats.Cat.YearsOfExperience, &stats.Cat.Breed,
+			&salary.amount, &salary.currency, &stats.CompletedMissions, &stats.RecentMissions, &stats.CountrySuccesses)
+		if err != nil {
+			return nil, err
+		}
+		if stats.Cat.Salary, err = salary.value(); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, stats)
+	}
+
+	return candidates, rows.Err()
+}
@@ -0,0 +1,11 @@
+package models
+
+// CatAvailability tells whether a cat can take a mission, Since is when the current state started
+type CatAvailability struct {
+	CatID         int           `json:"CatID"`
+	Available     bool          `json:"Available"`
+	MissionID     *int          `json:"MissionID,omitempty"`
+	MissionStatus MissionStatus `json:"MissionStatus,omitempty"`
+	Archived      bool          `json:"Archived,omitempty"`
+	Since         string        `json:"Since"`
+}
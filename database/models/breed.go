@@ -0,0 +1,26 @@
+package models
+
+type BreedVerification string
+
+const (
+	BreedVerificationPending  BreedVerification = "pending"
+	BreedVerificationVerified BreedVerification = "verified"
+	BreedVerificationRejected BreedVerification = "rejected"
+)
+
+func (v BreedVerification) IsValid() bool {
+	switch v {
+	case BreedVerificationPending, BreedVerificationVerified, BreedVerificationRejected:
+		return true
+	}
+	return false
+}
+
+// Breed is the canonical breed data stored on a cat profile
+type Breed struct {
+	ID          string `db:"breed_id" json:"ID"`
+	Name        string `db:"breed" json:"Name"`
+	Origin      string `db:"breed_origin" json:"Origin"`
+	Temperament string `db:"breed_temperament" json:"Temperament"`
+	LifeSpan    string `db:"breed_life_span" json:"LifeSpan"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// CandidateStats is the raw track record the candidate score is computed from
+type CandidateStats struct {
+	Cat               Cat
+	CompletedMissions int
+	RecentMissions    int
+	CountrySuccesses  int
+}
+
+// CandidateFactor is one line of the score breakdown, Points = Score * Weight * 100
+type CandidateFactor struct {
+	Factor string  `json:"Factor"`
+	Value  float64 `json:"Value"`
+	Score  float64 `json:"Score"`
+	Weight float64 `json:"Weight"`
+	Points float64 `json:"Points"`
+}
+
+type MissionCandidate struct {
+	CatID             int               `json:"CatID"`
+	Name              string            `json:"Name"`
+	Breed             string            `json:"Breed"`
+	YearsOfExperience int               `json:"YearsOfExperience"`
+	Salary            Money             `json:"Salary"`
+	Score             float64           `json:"Score"`
+	Eligible          bool              `json:"Eligible"`
+	UnmetRequirements []string          `json:"UnmetRequirements,omitempty"`
+	Breakdown         []CandidateFactor `json:"Breakdown"`
+}
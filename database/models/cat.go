@@ -1,11 +1,108 @@
 package models
 
+// Cat is a spy cat profile. For cats with a HireDate, YearsOfExperience is derived as PriorExperience plus
+// the full years since hiring; the stored value only counts for cats without one
 type Cat struct {
-	ID                int     `db:"id" json:"ID"`
-	Name              string  `db:"name" json:"Name" validate:"required"`
-	YearsOfExperience int     `db:"years_of_experience" json:"YearsOfExperience" validate:"required"`
-	Breed             string  `db:"breed" json:"Breed" validate:"required"`
-	Salary            float64 `db:"salary" json:"Salary" validate:"required"`
-	CreatedAt         string  `db:"created_at" json:"CreatedAt"`
-	UpdatedAt         string  `db:"updated_at" json:"UpdatedAt,omitempty"`
+	ID                int               `db:"id" json:"ID"`
+	Name              string            `db:"name" json:"Name" validate:"required,max=100"`
+	YearsOfExperience int               `db:"years_of_experience" json:"YearsOfExperience" validate:"required_without=HireDate"`
+	HireDate          string            `db:"hire_date" json:"HireDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	PriorExperience   int               `db:"prior_experience" json:"PriorExperience" validate:"min=0"`
+	Breed             string            `db:"breed" json:"Breed" validate:"required,max=100"`
+	Salary            Money             `db:"salary" json:"Salary"`
+	BreedDetails      *Breed            `json:"BreedDetails,omitempty"`
+	BreedVerification BreedVerification `db:"breed_verification" json:"BreedVerification"`
+	CreatedAt         string            `db:"created_at" json:"CreatedAt"`
+	UpdatedAt         string            `db:"updated_at" json:"UpdatedAt,omitempty"`
+	DeletedAt         string            `db:"deleted_at" json:"DeletedAt,omitempty"`
+}
+
+func (c Cat) IsArchived() bool {
+	return c.DeletedAt != ""
+}
+
+// CatPatch is a partial cat profile, only non-nil fields are updated. An empty HireDate clears it
+type CatPatch struct {
+	Name              *string `json:"Name"`
+	YearsOfExperience *int    `json:"YearsOfExperience"`
+	HireDate          *string `json:"HireDate"`
+	PriorExperience   *int    `json:"PriorExperience"`
+	Breed             *string `json:"Breed"`
+	Salary            *Money  `json:"Salary"`
+
+	// BreedDetails and BreedVerification are filled by the service once Breed is checked against the catalog
+	BreedDetails      *Breed             `json:"-"`
+	BreedVerification *BreedVerification `json:"-"`
+}
+
+func (p CatPatch) IsEmpty() bool {
+	return p.Name == nil && p.YearsOfExperience == nil && p.HireDate == nil && p.PriorExperience == nil &&
+		p.Breed == nil && p.Salary == nil
+}
+
+// Apply returns a copy of the cat with the patched fields overwritten
+func (p CatPatch) Apply(cat Cat) Cat {
+	if p.Name != nil {
+		cat.Name = *p.Name
+	}
+	if p.YearsOfExperience != nil {
+		cat.YearsOfExperience = *p.YearsOfExperience
+	}
+	if p.HireDate != nil {
+		cat.HireDate = *p.HireDate
+	}
+	if p.PriorExperience != nil {
+		cat.PriorExperience = *p.PriorExperience
+	}
+	if p.Breed != nil {
+		cat.Breed = *p.Breed
+	}
+	if p.Salary != nil {
+		cat.Salary = *p.Salary
+	}
+	return cat
+}
+
+// CatFilter narrows and orders a cat listing, zero values mean "no filter"
+type CatFilter struct {
+	Limit             int
+	Offset            int
+	Breed             string
+	BreedVerification BreedVerification
+	MinExperience     *int
+	MaxExperience     *int
+	MinSalary         *Money
+	MaxSalary         *Money
+	SortBy            string
+	SortDesc          bool
+	IncludeArchived   bool
+	Available         *bool
+	Skills            []string
+	MinProficiency    *int
+}
+
+type CatList struct {
+	Cats   []Cat `json:"Cats"`
+	Total  int   `json:"Total"`
+	Limit  int   `json:"Limit"`
+	Offset int   `json:"Offset"`
+}
+
+type CatDeletionMode string
+
+const (
+	// CatDeletionRefuse refuses to archive a cat that still has active missions
+	CatDeletionRefuse   CatDeletionMode = ""
+	CatDeletionReassign CatDeletionMode = "reassign"
+	CatDeletionUnassign CatDeletionMode = "unassign"
+)
+
+// CatDeletion says what happens to the active missions of a cat being archived. A replacement that fails the
+// mission requirements needs OverrideEligibility with an OverrideReason, the service then fills Override
+type CatDeletion struct {
+	Mode                CatDeletionMode
+	ReplacementCatID    int
+	OverrideEligibility bool
+	OverrideReason      string
+	Override            *EligibilityOverride
 }
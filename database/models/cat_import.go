@@ -0,0 +1,38 @@
+package models
+
+// CatImportMode decides what happens to the valid rows of an import that also has invalid ones
+type CatImportMode string
+
+const (
+	// CatImportAtomic creates every row in one transaction, or nothing when any row fails
+	CatImportAtomic CatImportMode = "atomic"
+	// CatImportBestEffort creates the valid rows and reports the others
+	CatImportBestEffort CatImportMode = "best_effort"
+)
+
+func (m CatImportMode) IsValid() bool {
+	return m == CatImportAtomic || m == CatImportBestEffort
+}
+
+// CatImportRecord is one parsed input row, ParseError is set when the row could not be read into a cat
+type CatImportRecord struct {
+	Row        int
+	Cat        Cat
+	ParseError string
+}
+
+type CatImportRow struct {
+	Row               int               `json:"Row"`
+	Name              string            `json:"Name,omitempty"`
+	CatID             int               `json:"CatID,omitempty"`
+	BreedVerification BreedVerification `json:"BreedVerification,omitempty"`
+	Errors            []string          `json:"Errors,omitempty"`
+}
+
+type CatImportReport struct {
+	Mode    CatImportMode  `json:"Mode"`
+	Total   int            `json:"Total"`
+	Created int            `json:"Created"`
+	Failed  int            `json:"Failed"`
+	Rows    []CatImportRow `json:"Rows"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// CatMatch is a cat found by similarity, Score goes from 0 to 1
+type CatMatch struct {
+	Cat   Cat     `json:"Cat"`
+	Score float64 `json:"Score"`
+}
+
+// CreatedCat is the outcome of CreateCat, PossibleDuplicates lists existing cats that look like the new one
+type CreatedCat struct {
+	ID                 int               `json:"ID"`
+	BreedVerification  BreedVerification `json:"BreedVerification"`
+	PossibleDuplicates []CatMatch        `json:"PossibleDuplicates,omitempty"`
+}
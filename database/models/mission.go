@@ -3,15 +3,89 @@ package models
 type MissionStatus string
 
 const (
+	MissionStatusDraft      MissionStatus = "draft"
+	MissionStatusUnassigned MissionStatus = "unassigned"
+	MissionStatusAssigned   MissionStatus = "assigned"
 	MissionStatusInProgress MissionStatus = "in_progress"
+	MissionStatusOnHold     MissionStatus = "on_hold"
+	MissionStatusAborted    MissionStatus = "aborted"
 	MissionStatusCompleted  MissionStatus = "completed"
 )
 
+// IsClosed reports whether the mission reached a final state
+func (s MissionStatus) IsClosed() bool {
+	return s == MissionStatusCompleted || s == MissionStatusAborted
+}
+
+// AwaitsCat reports whether the mission is still without a cat, either as a draft or in the dispatch queue
+func (s MissionStatus) AwaitsCat() bool {
+	return s == MissionStatusDraft || s == MissionStatusUnassigned
+}
+
+// MissionFilter narrows a mission listing, Unassigned keeps only the dispatch queue
+type MissionFilter struct {
+	Unassigned bool
+}
+
+// MissionTransition is a request to move a mission to another state
+type MissionTransition struct {
+	Status MissionStatus `json:"Status" validate:"required"`
+}
+
 type Mission struct {
 	ID        int           `db:"id" json:"ID"`
-	CatID     int           `db:"cat_id" json:"CatID" validate:"required"`
+	CatID     int           `db:"cat_id" json:"CatID"`
 	Status    MissionStatus `db:"status" json:"Status" validate:"required"`
 	Targets   []Target      `json:"Targets" validate:"required"`
 	CreatedAt string        `db:"created_at" json:"CreatedAt"`
 	UpdatedAt string        `db:"updated_at" json:"UpdatedAt,omitempty"`
+
+	Requirements        *MissionRequirements `json:"Requirements,omitempty"`
+	EligibilityOverride *EligibilityOverride `json:"EligibilityOverride,omitempty"`
+}
+
+// MissionRequirements restricts which cats can be assigned, nil or empty fields are not checked
+type MissionRequirements struct {
+	MinExperience *int     `db:"min_experience" json:"MinExperience,omitempty"`
+	AllowedBreeds []string `db:"allowed_breeds" json:"AllowedBreeds,omitempty"`
+	MaxSalary     *Money   `db:"max_salary" json:"MaxSalary,omitempty"`
+}
+
+func (r *MissionRequirements) IsEmpty() bool {
+	return r == nil || r.MinExperience == nil && len(r.AllowedBreeds) == 0 && r.MaxSalary == nil
+}
+
+// EligibilityOverride records a cat assigned despite unmet requirements
+type EligibilityOverride struct {
+	CatID             int      `db:"override_cat_id" json:"CatID"`
+	Reason            string   `db:"override_reason" json:"Reason"`
+	UnmetRequirements []string `db:"override_unmet" json:"UnmetRequirements"`
+	OverriddenAt      string   `db:"overridden_at" json:"OverriddenAt"`
+}
+
+// AssignmentOptions lets a dispatcher assign a cat that fails the mission requirements
+// or replace the cat already on the mission
+type AssignmentOptions struct {
+	OverrideEligibility bool   `json:"OverrideEligibility"`
+	OverrideReason      string `json:"OverrideReason"`
+	ReassignReason      string `json:"ReassignReason"`
+}
+
+// MissionHandoff moves an active mission to another cat. The Briefing is added to the notes of every open target
+type MissionHandoff struct {
+	CatID    int    `json:"CatID" validate:"required"`
+	Reason   string `json:"Reason" validate:"required"`
+	Briefing string `json:"Briefing" validate:"required"`
+	AssignmentOptions
+}
+
+// MissionAssignment is one cat's time on a mission, EndedAt is empty for the current cat
+type MissionAssignment struct {
+	ID        int    `json:"ID"`
+	MissionID int    `json:"MissionID"`
+	CatID     int    `json:"CatID"`
+	Reason    string `json:"Reason,omitempty"`
+	EndReason string `json:"EndReason,omitempty"`
+	StartedAt string `json:"StartedAt"`
+	EndedAt   string `json:"EndedAt,omitempty"`
 }
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCurrency = "USD"
+
+	moneyScale = 100
+	// MaxMoneyCents is the largest amount a DECIMAL(10, 2) column can hold
+	MaxMoneyCents = 99999999_99
+)
+
+var (
+	ErrNegativeMoney   = errors.New("amount cannot be negative")
+	ErrMoneyPrecision  = errors.New("amount cannot have more than 2 decimal places")
+	ErrMoneyOutOfRange = errors.New("amount is too large")
+	ErrInvalidMoney    = errors.New("amount must be a decimal number such as 1999.99")
+	ErrInvalidCurrency = errors.New("currency must be a 3-letter ISO 4217 code such as USD")
+)
+
+// Money is a fixed-point amount in cents with an explicit currency code.
+// In JSON it is {"Amount": "1999.99", "Currency": "USD"} so no precision is lost to floats
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// ParseMoney parses a decimal amount exactly, an empty currency means DefaultCurrency
+func ParseMoney(amount, currency string) (Money, error) {
+	cents, err := parseCents(amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isCurrencyCode(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+
+	return Money{Cents: cents, Currency: currency}, nil
+}
+
+func parseCents(amount string) (int64, error) {
+	amount = strings.TrimSpace(amount)
+	if strings.HasPrefix(amount, "-") {
+		return 0, ErrNegativeMoney
+	}
+	amount = strings.TrimPrefix(amount, "+")
+
+	whole, frac, hasFrac := strings.Cut(amount, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || hasFrac && (frac == "" || !isDigits(frac)) {
+		return 0, ErrInvalidMoney
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return 0, ErrMoneyPrecision
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > 8 {
+		return 0, ErrMoneyOutOfRange
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	if cents > MaxMoneyCents {
+		return 0, ErrMoneyOutOfRange
+	}
+
+	return cents, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal is the exact amount without currency, e.g. "1999.90", suitable for DECIMAL columns
+func (m Money) Decimal() string {
+	return fmt.Sprintf("%d.%02d", m.Cents/moneyScale, m.Cents%moneyScale)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.Cents == other.Cents && m.Currency == other.Currency
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"Amount"`
+	Currency string          `json:"Currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"Amount"`
+		Currency string `json:"Currency"`
+	}{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts {"Amount": "1999.99", "Currency": "USD"}, the amount may also be a JSON number.
+// A bare number or string is read as an amount in DefaultCurrency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	raw := moneyJSON{Amount: data}
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	}
+
+	amount, err := rawAmount(raw.Amount)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// rawAmount keeps the literal text of a JSON number so it never goes through float64
+func rawAmount(data json.RawMessage) (string, error) {
+	if len(data) == 0 {
+		return "", ErrInvalidMoney
+	}
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", ErrInvalidMoney
+	}
+	if strings.ContainsAny(n.String(), "eE") {
+		return "", ErrInvalidMoney
+	}
+	return n.String(), nil
+}
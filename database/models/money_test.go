@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "whole amount", amount: "1999", want: Money{Cents: 199900, Currency: "USD"}},
+		{name: "two decimals", amount: "1999.99", currency: "EUR", want: Money{Cents: 199999, Currency: "EUR"}},
+		{name: "one decimal", amount: "12.5", want: Money{Cents: 1250, Currency: "USD"}},
+		{name: "trailing zeros are not extra precision", amount: "12.500", want: Money{Cents: 1250, Currency: "USD"}},
+		{name: "leading zeros and plus", amount: " +0007.10 ", want: Money{Cents: 710, Currency: "USD"}},
+		{name: "fraction only", amount: ".5", want: Money{Cents: 50, Currency: "USD"}},
+		{name: "lowercase currency", amount: "1", currency: " gbp ", want: Money{Cents: 100, Currency: "GBP"}},
+		{name: "largest amount", amount: "99999999.99", want: Money{Cents: MaxMoneyCents, Currency: "USD"}},
+		{name: "too many digits", amount: "100000000", wantErr: ErrMoneyOutOfRange},
+		{name: "int64 overflow", amount: "99999999999999999999999", wantErr: ErrMoneyOutOfRange},
+		{name: "sub-cent amount is not rounded", amount: "1.005", wantErr: ErrMoneyPrecision},
+		{name: "negative", amount: "-1", wantErr: ErrNegativeMoney},
+		{name: "empty", amount: "", wantErr: ErrInvalidMoney},
+		{name: "dot only", amount: ".", wantErr: ErrInvalidMoney},
+		{name: "trailing dot", amount: "1.", wantErr: ErrInvalidMoney},
+		{name: "exponent", amount: "1e3", wantErr: ErrInvalidMoney},
+		{name: "letters", amount: "12a", wantErr: ErrInvalidMoney},
+		{name: "bad currency", amount: "1", currency: "DOLLARS", wantErr: ErrInvalidCurrency},
+		{name: "currency with digits", amount: "1", currency: "US1", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{name: "object with string amount", input: `{"Amount": "1999.99", "Currency": "EUR"}`, want: Money{Cents: 199999, Currency: "EUR"}},
+		{name: "object with number amount", input: `{"Amount": 1999.9, "Currency": "jpy"}`, want: Money{Cents: 199990, Currency: "JPY"}},
+		{name: "object without currency", input: `{"Amount": "5"}`, want: Money{Cents: 500, Currency: "USD"}},
+		{name: "bare number", input: `5000.25`, want: Money{Cents: 500025, Currency: "USD"}},
+		{name: "bare string", input: `"5000.25"`, want: Money{Cents: 500025, Currency: "USD"}},
+		{name: "number beyond float precision is kept exact", input: `99999999.99`, want: Money{Cents: MaxMoneyCents, Currency: "USD"}},
+		{name: "null leaves the value unset", input: `null`},
+		{name: "object without amount", input: `{"Currency": "USD"}`, wantErr: true},
+		{name: "exponent number", input: `1e3`, wantErr: true},
+		{name: "too precise number", input: `1.001`, wantErr: true},
+		{name: "overflowing number", input: `1000000000`, wantErr: true},
+		{name: "negative object", input: `{"Amount": "-3", "Currency": "USD"}`, wantErr: true},
+		{name: "boolean", input: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMarshalJSONRoundTrip(t *testing.T) {
+	original := Money{Cents: 120005, Currency: "GBP"}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Amount":"1200.05","Currency":"GBP"}` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(original) {
+		t.Fatalf("round trip gave %+v, want %+v", decoded, original)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+// SalaryBand is the inclusive salary range allowed for a rank, in a single currency
+type SalaryBand struct {
+	Min Money `json:"Min"`
+	Max Money `json:"Max"`
+}
+
+// AppliesTo reports whether the salary is in the band's currency. There are no exchange rates,
+// so salaries in other currencies cannot be checked against the band
+func (b SalaryBand) AppliesTo(salary Money) bool {
+	return salary.Currency == b.Min.Currency
+}
+
+func (b SalaryBand) Contains(salary Money) bool {
+	return salary.Currency == b.Min.Currency && salary.Cents >= b.Min.Cents && salary.Cents <= b.Max.Cents
+}
+
+// Rank is reached once a cat has both the experience and the completed missions it requires
+type Rank struct {
+	Name                 string     `json:"Name"`
+	MinExperience        int        `json:"MinExperience"`
+	MinCompletedMissions int        `json:"MinCompletedMissions"`
+	SalaryBand           SalaryBand `json:"SalaryBand"`
+}
+
+// RankLadder lists the ranks from the lowest to the highest, the first one has no requirements
+type RankLadder []Rank
+
+// For returns the index of the highest rank the cat qualifies for
+func (l RankLadder) For(experience, completedMissions int) int {
+	current := 0
+	for i, rank := range l {
+		if experience >= rank.MinExperience && completedMissions >= rank.MinCompletedMissions {
+			current = i
+		}
+	}
+	return current
+}
+
+// RankStats is what a cat's rank is computed from, Rank is the last rank recorded by the promotion job
+type RankStats struct {
+	CatID             int
+	YearsOfExperience int
+	CompletedMissions int
+	Rank              string
+}
+
+// RankProgress tells what is still missing to reach the next rank
+type RankProgress struct {
+	Rank              string `json:"Rank"`
+	MissingExperience int    `json:"MissingExperience"`
+	MissingMissions   int    `json:"MissingMissions"`
+}
+
+// CatRank explains a cat's rank, RecordedRank lags behind Rank until the promotion job runs
+type CatRank struct {
+	CatID             int           `json:"CatID"`
+	Rank              string        `json:"Rank"`
+	RecordedRank      string        `json:"RecordedRank,omitempty"`
+	YearsOfExperience int           `json:"YearsOfExperience"`
+	CompletedMissions int           `json:"CompletedMissions"`
+	SalaryBand        SalaryBand    `json:"SalaryBand"`
+	Explanation       []string      `json:"Explanation"`
+	Next              *RankProgress `json:"Next,omitempty"`
+	Promotions        []Promotion   `json:"Promotions"`
+}
+
+// Promotion is a recorded rank change, FromRank is empty for the cat's first rank
+type Promotion struct {
+	ID                int    `json:"ID"`
+	CatID             int    `json:"CatID"`
+	FromRank          string `json:"FromRank,omitempty"`
+	ToRank            string `json:"ToRank"`
+	YearsOfExperience int    `json:"YearsOfExperience"`
+	CompletedMissions int    `json:"CompletedMissions"`
+	PromotedAt        string `json:"PromotedAt"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestSalaryBand(t *testing.T) {
+	band := SalaryBand{Min: Money{Cents: 100000, Currency: "USD"}, Max: Money{Cents: 300000, Currency: "USD"}}
+
+	tests := []struct {
+		name         string
+		salary       Money
+		wantApplies  bool
+		wantContains bool
+	}{
+		{name: "inside", salary: Money{Cents: 200000, Currency: "USD"}, wantApplies: true, wantContains: true},
+		{name: "at the minimum", salary: Money{Cents: 100000, Currency: "USD"}, wantApplies: true, wantContains: true},
+		{name: "at the maximum", salary: Money{Cents: 300000, Currency: "USD"}, wantApplies: true, wantContains: true},
+		{name: "below", salary: Money{Cents: 99999, Currency: "USD"}, wantApplies: true},
+		{name: "above", salary: Money{Cents: 300001, Currency: "USD"}, wantApplies: true},
+		{name: "other currency", salary: Money{Cents: 200000, Currency: "EUR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := band.AppliesTo(tt.salary); got != tt.wantApplies {
+				t.Errorf("AppliesTo = %v, want %v", got, tt.wantApplies)
+			}
+			if got := band.Contains(tt.salary); got != tt.wantContains {
+				t.Errorf("Contains = %v, want %v", got, tt.wantContains)
+			}
+		})
+	}
+}
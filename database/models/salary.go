@@ -0,0 +1,46 @@
+package models
+
+const DateLayout = "2006-01-02"
+
+// SalaryChangeStatus tracks the supervisor approval of a salary change, only approved changes are applied
+type SalaryChangeStatus string
+
+const (
+	SalaryChangePending  SalaryChangeStatus = "pending"
+	SalaryChangeApproved SalaryChangeStatus = "approved"
+	SalaryChangeRejected SalaryChangeStatus = "rejected"
+)
+
+func (s SalaryChangeStatus) IsValid() bool {
+	return s == SalaryChangePending || s == SalaryChangeApproved || s == SalaryChangeRejected
+}
+
+// SalaryChange is one entry of a cat's payroll ledger, AppliedAt stays empty while a raise is scheduled or pending
+type SalaryChange struct {
+	ID            int                `db:"id" json:"ID"`
+	CatID         int                `db:"cat_id" json:"CatID"`
+	OldSalary     *Money             `db:"old_salary" json:"OldSalary"`
+	NewSalary     Money              `db:"new_salary" json:"NewSalary"`
+	EffectiveDate string             `db:"effective_date" json:"EffectiveDate"`
+	Reason        string             `db:"reason" json:"Reason"`
+	Status        SalaryChangeStatus `db:"status" json:"Status"`
+	ReviewedBy    string             `db:"reviewed_by" json:"ReviewedBy,omitempty"`
+	ReviewNote    string             `db:"review_note" json:"ReviewNote,omitempty"`
+	ReviewedAt    string             `db:"reviewed_at" json:"ReviewedAt,omitempty"`
+	AppliedAt     string             `db:"applied_at" json:"AppliedAt,omitempty"`
+	CreatedAt     string             `db:"created_at" json:"CreatedAt"`
+}
+
+func (sc SalaryChange) IsScheduled() bool {
+	return sc.AppliedAt == ""
+}
+
+func (sc SalaryChange) IsPending() bool {
+	return sc.Status == SalaryChangePending
+}
+
+// SalaryReview is a supervisor's decision on a pending salary change
+type SalaryReview struct {
+	ReviewedBy string `json:"ReviewedBy" validate:"required"`
+	Note       string `json:"Note"`
+}
@@ -0,0 +1,23 @@
+package models
+
+const (
+	MinProficiency = 1
+	MaxProficiency = 5
+)
+
+type Skill struct {
+	ID          int    `db:"id" json:"ID"`
+	Name        string `db:"name" json:"Name" validate:"required"`
+	Description string `db:"description" json:"Description"`
+	CreatedAt   string `db:"created_at" json:"CreatedAt"`
+}
+
+// CatSkill links a cat to a catalog skill with a proficiency from 1 (novice) to 5 (master)
+type CatSkill struct {
+	CatID       int    `db:"cat_id" json:"CatID"`
+	SkillID     int    `db:"skill_id" json:"SkillID" validate:"required"`
+	SkillName   string `db:"name" json:"SkillName"`
+	Proficiency int    `db:"proficiency" json:"Proficiency" validate:"required,min=1,max=5"`
+	CreatedAt   string `db:"created_at" json:"CreatedAt"`
+	UpdatedAt   string `db:"updated_at" json:"UpdatedAt,omitempty"`
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"database/sql"
+	"spyCat/database/models"
+)
+
+// moneyDest scans a DECIMAL amount column and its currency column, pq returns DECIMAL as text so no float is involved
+type moneyDest struct {
+	amount   sql.NullString
+	currency sql.NullString
+}
+
+// money returns nil for a NULL amount
+func (d *moneyDest) money() (*models.Money, error) {
+	if !d.amount.Valid {
+		return nil, nil
+	}
+
+	m, err := models.ParseMoney(d.amount.String, d.currency.String)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (d *moneyDest) value() (models.Money, error) {
+	m, err := d.money()
+	if err != nil || m == nil {
+		return models.Money{}, err
+	}
+	return *m, nil
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"database/sql"
+	"spyCat/database/models"
+	"time"
+)
+
+type SkillDatabaseInterface interface {
+	SelectAllSkills() ([]models.Skill, error)
+	InsertSkill(skill *models.Skill) error
+	SelectCatSkills(catID int) ([]models.CatSkill, error)
+	SelectCatSkill(catID, skillID int) (*models.CatSkill, error)
+	InsertCatSkill(catSkill *models.CatSkill) error
+	UpdateCatSkill(catID, skillID, proficiency int) error
+	DeleteCatSkill(catID, skillID int) error
+	IsCatArchived(catID int) (bool, error)
+	DoesSkillExist(skillID int) (bool, error)
+}
+
+type SkillDatabase struct {
+	*Database
+}
+
+func NewSkillDatabase(Conn *Database) *SkillDatabase {
+	return &SkillDatabase{Conn}
+}
+
+func (sd *SkillDatabase) SelectAllSkills() ([]models.Skill, error) {
+	var createdAt time.Time
+	skills := []models.Skill{}
+
+	rows, err := sd.Connection.Query(`SELECT id, name, description, created_at FROM skills ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var skill models.Skill
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.Description, &createdAt); err != nil {
+			return nil, err
+		}
+		skill.CreatedAt = createdAt.Format("15:04:05 02:01:06")
+		skills = append(skills, skill)
+	}
+
+	return skills, rows.Err()
+}
+
+func (sd *SkillDatabase) InsertSkill(skill *models.Skill) error {
+	var createdAt time.Time
+	err := sd.Connection.QueryRow(`
+		INSERT INTO skills (name, description) VALUES ($1, $2)
+		RETURNING id, created_at
+	`, skill.Name, skill.Description).Scan(&skill.ID, &createdAt)
+	if err != nil {
+		return err
+	}
+
+	skill.CreatedAt = createdAt.Format("15:04:05 02:01:06")
+	return nil
+}
+
+const catSkillQuery = `
+	SELECT cs.cat_id, cs.skill_id, s.name, cs.proficiency, cs.created_at, cs.updated_at
+	FROM cat_skills cs
+	JOIN skills s ON s.id = cs.skill_id`
+
+func scanCatSkill(row rowScanner) (*models.CatSkill, error) {
+	var catSkill models.CatSkill
+	var createdAt, updatedAt time.Time
+
+	err := row.Scan(&catSkill.CatID, &catSkill.SkillID, &catSkill.SkillName, &catSkill.Proficiency, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	catSkill.CreatedAt = createdAt.Format("15:04:05 02:01:06")
+	catSkill.UpdatedAt = updatedAt.Format("15:04:05 02:01:06")
+	return &catSkill, nil
+}
+
+func (sd *SkillDatabase) SelectCatSkills(catID int) ([]models.CatSkill, error) {
+	catSkills := []models.CatSkill{}
+
+	rows, err := sd.Connection.Query(catSkillQuery+` WHERE cs.cat_id = $1 ORDER BY cs.proficiency DESC, s.name`, catID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		catSkill, err := scanCatSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		catSkills = append(catSkills, *catSkill)
+	}
+
+	return catSkills, rows.Err()
+}
+
+func (sd *SkillDatabase) SelectCatSkill(catID, skillID int) (*models.CatSkill, error) {
+	return scanCatSkill(sd.Connection.QueryRow(catSkillQuery+` WHERE cs.cat_id = $1 AND cs.skill_id = $2`, catID, skillID))
+}
+
+func (sd *SkillDatabase) InsertCatSkill(catSkill *models.CatSkill) error {
+	_, err := sd.Connection.Exec(`
+		INSERT INTO cat_skills (cat_id, skill_id, proficiency) VALUES ($1, $2, $3)
+	`, catSkill.CatID, catSkill.SkillID, catSkill.Proficiency)
+	return err
+}
+
+func (sd *SkillDatabase) UpdateCatSkill(catID, skillID, proficiency int) error {
+	result, err := sd.Connection.Exec(`
+		UPDATE cat_skills SET proficiency = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE cat_id = $2 AND skill_id = $3
+	`, proficiency, catID, skillID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (sd *SkillDatabase) DeleteCatSkill(catID, skillID int) error {
+	result, err := sd.Connection.Exec(`DELETE FROM cat_skills WHERE cat_id = $1 AND skill_id = $2`, catID, skillID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// IsCatArchived returns sql.ErrNoRows when the cat does not exist
+func (sd *SkillDatabase) IsCatArchived(catID int) (bool, error) {
+	var archived bool
+	err := sd.Connection.QueryRow("SELECT deleted_at IS NOT NULL FROM spy_cats WHERE id = $1", catID).Scan(&archived)
+	if err != nil {
+		return false, err
+	}
+	return archived, nil
+}
+
+func (sd *SkillDatabase) DoesSkillExist(skillID int) (bool, error) {
+	var exists bool
+	err := sd.Connection.QueryRow("SELECT EXISTS(SELECT 1 FROM skills WHERE id = $1)", skillID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -111,8 +111,9 @@ func (td *TargetDatabase) UpdateTargetNotes(targetID int, notes string) error {
 	return err
 }
 
+// IsMissionCompleted reports whether the mission is closed, aborted missions are as final as completed ones
 func (td *TargetDatabase) IsMissionCompleted(missionID int) (bool, error) {
-	var status string
+	var status models.MissionStatus
 	err := td.Connection.QueryRow("SELECT status FROM missions WHERE id = $1", missionID).Scan(&status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -120,7 +121,7 @@ func (td *TargetDatabase) IsMissionCompleted(missionID int) (bool, error) {
 		}
 		return false, err
 	}
-	return status == "completed", nil
+	return status.IsClosed(), nil
 }
 
 func (td *TargetDatabase) GetMission(id int) (*models.Mission, error) {
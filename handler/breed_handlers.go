@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"spyCat/response"
+	"spyCat/service"
+)
+
+type BreedHandler struct {
+	breedService service.BreedServiceInterface
+}
+
+func NewBreedHandler(service service.BreedServiceInterface) *BreedHandler {
+	return &BreedHandler{breedService: service}
+}
+
+type BreedHandlerInterface interface {
+	ListBreeds(c echo.Context) error
+	GetCacheStats(c echo.Context) error
+}
+
+// ListBreeds lists the breed catalog, ?q= narrows it down by prefix or fuzzy match for autocomplete
+func (bh *BreedHandler) ListBreeds(c echo.Context) error {
+	breeds, err, respStatus := bh.breedService.ListBreeds(c.QueryParam("q"))
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": breeds}})
+}
+
+// GetCacheStats reports breed cache hit, miss and refresh failure counters
+func (bh *BreedHandler) GetCacheStats(c echo.Context) error {
+	stats := bh.breedService.CacheStats()
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": stats}})
+}
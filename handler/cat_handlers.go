@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"spyCat/database/models"
 	"spyCat/response"
 	"spyCat/service"
 	"strconv"
+	"strings"
 )
 
 type CatHandler struct {
@@ -19,12 +22,23 @@ func NewCatHandler(service service.CatServiceInterface) *CatHandler {
 
 type CatHandlerInterface interface {
 	UpdateCatSalary(c echo.Context) error
+	UpdateCat(c echo.Context) error
+	GetSalaryHistory(c echo.Context) error
 	CreateCat(c echo.Context) error
 	GetCat(c echo.Context) error
 	GetAllCats(c echo.Context) error
 	DeleteCat(c echo.Context) error
+	RestoreCat(c echo.Context) error
+	GetCatAvailability(c echo.Context) error
+	ImportCats(c echo.Context) error
+	SearchCats(c echo.Context) error
+	GetCatRank(c echo.Context) error
+	ListSalaryChanges(c echo.Context) error
+	ApproveSalaryChange(c echo.Context) error
+	RejectSalaryChange(c echo.Context) error
 }
 
+// CreateCat creates a cat, existing cats that look like it come back as a warning, or refuse it with ?strict=true
 func (ch *CatHandler) CreateCat(c echo.Context) error {
 	var cat models.Cat
 
@@ -32,12 +46,41 @@ func (ch *CatHandler) CreateCat(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	catID, err, respStatus := ch.catService.CreateCat(cat)
+	strict, err := queryBoolPtr(c, "strict")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	created, err, respStatus := ch.catService.CreateCat(cat, strict != nil && *strict)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: catErrorData(err)})
+	}
+
+	data := echo.Map{"use this ID to interact with cat`s profile": created.ID}
+	if respStatus == http.StatusAccepted {
+		data["BreedVerification"] = models.BreedVerificationPending
+	}
+	if len(created.PossibleDuplicates) > 0 {
+		data["warning"] = fmt.Sprintf("%d cat(s) with a similar name and the same breed already exist", len(created.PossibleDuplicates))
+		data["possibleDuplicates"] = created.PossibleDuplicates
+	}
+
+	return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "success", Data: &data})
+}
+
+// SearchCats fuzzy matches ?q= against cat names and breeds
+func (ch *CatHandler) SearchCats(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	matches, err, respStatus := ch.catService.SearchCats(c.QueryParam("q"), limit)
 	if err != nil {
 		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	return c.JSON(http.StatusCreated, response.UserResponse{Status: http.StatusCreated, Message: "success", Data: &echo.Map{"use this ID to interact with cat`s profile": catID}})
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": matches}})
 }
 
 func (ch *CatHandler) GetCat(c echo.Context) error {
@@ -63,31 +106,233 @@ func (ch *CatHandler) UpdateCatSalary(c echo.Context) error {
 	}
 
 	var salaryUpdate struct {
-		Salary float64 `json:"salary"`
+		Salary        models.Money `json:"salary"`
+		EffectiveDate string       `json:"effective_date"`
+		Reason        string       `json:"reason"`
 	}
 
 	if err := c.Bind(&salaryUpdate); err != nil {
 		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
 	}
 
-	err, respStatus := ch.catService.EditCatSalary(catID, salaryUpdate.Salary)
+	change, err, respStatus := ch.catService.EditCatSalary(catID, models.SalaryChange{
+		NewSalary:     salaryUpdate.Salary,
+		EffectiveDate: salaryUpdate.EffectiveDate,
+		Reason:        salaryUpdate.Reason,
+	})
 	if err != nil {
-		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: catErrorData(err)})
+	}
+
+	if change.IsPending() {
+		resp := fmt.Sprintf("the raise needs supervisor approval, salary change %d is pending", change.ID)
+		return c.JSON(http.StatusAccepted, response.UserResponse{Status: http.StatusAccepted, Message: "success",
+			Data: &echo.Map{"data": resp, "change": change}})
+	}
+
+	if change.IsScheduled() {
+		resp := fmt.Sprintf("cat salary change scheduled for %s", change.EffectiveDate)
+		return c.JSON(http.StatusAccepted, response.UserResponse{Status: http.StatusAccepted, Message: "success",
+			Data: &echo.Map{"data": resp, "change": change}})
 	}
 
 	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success",
-		Data: &echo.Map{"data": "cat salary successfully updated"}})
+		Data: &echo.Map{"data": "cat salary successfully updated", "change": change}})
+}
+
+func (ch *CatHandler) GetSalaryHistory(c echo.Context) error {
+	ID := c.Param("id")
+	catID, err := strconv.Atoi(ID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	history, err, respStatus := ch.catService.GetSalaryHistory(catID)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": history}})
+}
+
+func (ch *CatHandler) UpdateCat(c echo.Context) error {
+	ID := c.Param("id")
+	catID, err := strconv.Atoi(ID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	var patch models.CatPatch
+	if err := c.Bind(&patch); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
+	}
+
+	cat, err, respStatus := ch.catService.EditCat(catID, patch)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: catErrorData(err)})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": cat}})
 }
 
 func (ch *CatHandler) GetAllCats(c echo.Context) error {
-	cats, err := ch.catService.GetAllCats()
+	filter, err := parseCatFilter(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, response.UserResponse{Status: http.StatusInternalServerError, Message: "error", Data: &echo.Map{"data": err.Error()}})
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	cats, err, respStatus := ch.catService.GetAllCats(filter)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
 	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": cats}})
 }
 
+func (ch *CatHandler) GetCatAvailability(c echo.Context) error {
+	ID := c.Param("id")
+	catID, err := strconv.Atoi(ID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	availability, err, respStatus := ch.catService.GetCatAvailability(catID)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": availability}})
+}
+
+// catErrorData adds "did you mean" breed suggestions, blocking missions, the violated salary band or
+// possible duplicates to the error payload when there are any
+func catErrorData(err error) *echo.Map {
+	data := echo.Map{"data": err.Error()}
+
+	var breedErr *service.InvalidBreedError
+	if errors.As(err, &breedErr) && len(breedErr.Suggestions) > 0 {
+		data["suggestions"] = breedErr.Suggestions
+	}
+
+	var missionsErr *service.ActiveMissionsError
+	if errors.As(err, &missionsErr) {
+		data["activeMissions"] = missionsErr.MissionIDs
+	}
+
+	var eligibilityErr *service.IneligibleCatError
+	if errors.As(err, &eligibilityErr) {
+		data["unmetRequirements"] = eligibilityErr.Unmet
+	}
+
+	var bandErr *service.SalaryBandError
+	if errors.As(err, &bandErr) {
+		data["rank"] = bandErr.Rank
+		data["salaryBand"] = bandErr.Band
+	}
+
+	var duplicateErr *service.DuplicateCatError
+	if errors.As(err, &duplicateErr) {
+		data["possibleDuplicates"] = duplicateErr.Matches
+	}
+
+	return &data
+}
+
+// parseCatFilter reads the listing query params, sort accepts a column name with an optional "-" prefix for descending order
+func parseCatFilter(c echo.Context) (models.CatFilter, error) {
+	var filter models.CatFilter
+	var err error
+
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		return filter, err
+	}
+	if filter.MinExperience, err = queryIntPtr(c, "min_experience"); err != nil {
+		return filter, err
+	}
+	if filter.MaxExperience, err = queryIntPtr(c, "max_experience"); err != nil {
+		return filter, err
+	}
+	if filter.MinSalary, err = queryMoneyPtr(c, "min_salary"); err != nil {
+		return filter, err
+	}
+	if filter.MaxSalary, err = queryMoneyPtr(c, "max_salary"); err != nil {
+		return filter, err
+	}
+
+	if filter.Available, err = queryBoolPtr(c, "available"); err != nil {
+		return filter, err
+	}
+	if filter.MinProficiency, err = queryIntPtr(c, "min_proficiency"); err != nil {
+		return filter, err
+	}
+	if skills := c.QueryParam("skill"); skills != "" {
+		filter.Skills = strings.Split(skills, ",")
+	}
+
+	filter.Breed = c.QueryParam("breed")
+	filter.BreedVerification = models.BreedVerification(c.QueryParam("breed_verification"))
+	filter.IncludeArchived = c.QueryParam("include_archived") == "true"
+	filter.SortBy = c.QueryParam("sort")
+	if strings.HasPrefix(filter.SortBy, "-") {
+		filter.SortBy = strings.TrimPrefix(filter.SortBy, "-")
+		filter.SortDesc = true
+	}
+
+	return filter, nil
+}
+
+func queryInt(c echo.Context, name string) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: must be an integer", name)
+	}
+	return n, nil
+}
+
+func queryIntPtr(c echo.Context, name string) (*int, error) {
+	if c.QueryParam(name) == "" {
+		return nil, nil
+	}
+	n, err := queryInt(c, name)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func queryBoolPtr(c echo.Context, name string) (*bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be true or false", name)
+	}
+	return &b, nil
+}
+
+// queryMoneyPtr reads an amount in the currency given by ?salary_currency=, DefaultCurrency when absent
+func queryMoneyPtr(c echo.Context, name string) (*models.Money, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	m, err := models.ParseMoney(value, c.QueryParam("salary_currency"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &m, nil
+}
+
+// DeleteCat archives a cat, ?mode=reassign&replacement_id= or ?mode=unassign handles its active missions
 func (ch *CatHandler) DeleteCat(c echo.Context) error {
 	ID := c.Param("id")
 	catID, err := strconv.Atoi(ID)
@@ -95,10 +340,97 @@ func (ch *CatHandler) DeleteCat(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	err, respStatus := ch.catService.DeleteCat(catID)
+	deletion := models.CatDeletion{Mode: models.CatDeletionMode(c.QueryParam("mode"))}
+	if deletion.ReplacementCatID, err = queryInt(c, "replacement_id"); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+	override, err := queryBoolPtr(c, "override_eligibility")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+	deletion.OverrideEligibility = override != nil && *override
+	deletion.OverrideReason = c.QueryParam("override_reason")
+
+	missionIDs, err, respStatus := ch.catService.DeleteCat(catID, deletion)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: catErrorData(err)})
+	}
+
+	data := echo.Map{"data": "cat successfully archived"}
+	switch {
+	case len(missionIDs) == 0:
+	case deletion.Mode == models.CatDeletionReassign:
+		data["reassignedMissions"] = missionIDs
+	case deletion.Mode == models.CatDeletionUnassign:
+		data["unassignedMissions"] = missionIDs
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &data})
+}
+
+func (ch *CatHandler) RestoreCat(c echo.Context) error {
+	ID := c.Param("id")
+	catID, err := strconv.Atoi(ID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	cat, err, respStatus := ch.catService.RestoreCat(catID)
 	if err != nil {
 		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": "cat successfully deleted"}})
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": cat}})
+}
+
+// GetCatRank explains the cat's rank, what the next one takes and its promotion history
+func (ch *CatHandler) GetCatRank(c echo.Context) error {
+	catID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	rank, err, respStatus := ch.catService.GetCatRank(catID)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": rank}})
+}
+
+// ListSalaryChanges lists salary changes by ?status=, pending ones awaiting a supervisor by default
+func (ch *CatHandler) ListSalaryChanges(c echo.Context) error {
+	changes, err, respStatus := ch.catService.ListSalaryChanges(models.SalaryChangeStatus(c.QueryParam("status")))
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": changes}})
+}
+
+func (ch *CatHandler) ApproveSalaryChange(c echo.Context) error {
+	return ch.reviewSalaryChange(c, true)
+}
+
+func (ch *CatHandler) RejectSalaryChange(c echo.Context) error {
+	return ch.reviewSalaryChange(c, false)
+}
+
+func (ch *CatHandler) reviewSalaryChange(c echo.Context, approve bool) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	var review models.SalaryReview
+	if err := c.Bind(&review); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
+	}
+
+	change, err, respStatus := ch.catService.ReviewSalaryChange(id, approve, review)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: catErrorData(err)})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": change}})
 }
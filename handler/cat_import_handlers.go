@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"spyCat/database/models"
+	"spyCat/response"
+	"spyCat/service"
+	"strconv"
+	"strings"
+)
+
+// catImportColumns are the required CSV header names. currency (defaults to USD), years_of_experience,
+// hire_date and prior_experience are optional
+var catImportColumns = []string{"name", "breed", "salary"}
+
+// ImportCats creates cats in bulk from a CSV file (Content-Type text/csv) or a JSON array of cats.
+// ?mode=best_effort creates the valid rows even when others fail
+func (ch *CatHandler) ImportCats(c echo.Context) error {
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, service.MaxCatImportBytes)
+
+	// reading stops one row past the limit, enough for the service to refuse the import
+	var records []models.CatImportRecord
+	var err error
+	if isCSV(c.Request().Header.Get(echo.HeaderContentType)) {
+		records, err = parseCatCSV(body, service.MaxCatImportRows+1)
+	} else {
+		records, err = parseCatJSON(body, service.MaxCatImportRows+1)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		msg := fmt.Sprintf("an import is limited to %d bytes, split the file", service.MaxCatImportBytes)
+		return c.JSON(http.StatusRequestEntityTooLarge, response.UserResponse{Status: http.StatusRequestEntityTooLarge, Message: "error", Data: &echo.Map{"data": msg}})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	report, err, respStatus := ch.catService.ImportCats(records, models.CatImportMode(c.QueryParam("mode")))
+	if err != nil {
+		data := echo.Map{"data": err.Error()}
+		if report != nil {
+			data["report"] = report
+		}
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &data})
+	}
+
+	return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "success", Data: &echo.Map{"data": report}})
+}
+
+func isCSV(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	return mediaType == "text/csv" || mediaType == "application/csv"
+}
+
+// parseCatJSON reads a JSON array of cats element by element, stopping after maxRows of them.
+// A malformed element only fails its own row
+func parseCatJSON(body io.Reader, maxRows int) ([]models.CatImportRecord, error) {
+	decoder := json.NewDecoder(body)
+	errNotArray := errors.New("the body must be a JSON array of cats")
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, bodyError(err, errNotArray)
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errNotArray
+	}
+
+	var records []models.CatImportRecord
+	for decoder.More() && len(records) < maxRows {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return nil, bodyError(err, errNotArray)
+		}
+
+		record := models.CatImportRecord{Row: len(records) + 1}
+		if err := json.Unmarshal(element, &record.Cat); err != nil {
+			record.ParseError = err.Error()
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// bodyError keeps the error of a body over its size limit and replaces any other with fallback
+func bodyError(err, fallback error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return fallback
+}
+
+// parseCatCSV reads a CSV with a header row naming catImportColumns in any order, stopping after maxRows rows.
+// Rows are numbered from 1 after the header
+func parseCatCSV(body io.Reader, maxRows int) ([]models.CatImportRecord, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, bodyError(err, errors.New("the CSV must start with a header row"))
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range catImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("the CSV header is missing the %q column", name)
+		}
+	}
+
+	var records []models.CatImportRecord
+	for row := 1; row <= maxRows; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		record := models.CatImportRecord{Row: row}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			record.ParseError = parseErr.Err.Error()
+			records = append(records, record)
+			continue
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+
+		record.Cat.Name = field("name")
+		record.Cat.Breed = field("breed")
+		record.Cat.HireDate = field("hire_date")
+		var errs []string
+		if experience := field("years_of_experience"); experience != "" {
+			if record.Cat.YearsOfExperience, err = strconv.Atoi(experience); err != nil {
+				errs = append(errs, "years_of_experience must be a whole number")
+			}
+		}
+		if prior := field("prior_experience"); prior != "" {
+			if record.Cat.PriorExperience, err = strconv.Atoi(prior); err != nil {
+				errs = append(errs, "prior_experience must be a whole number")
+			}
+		}
+		if record.Cat.Salary, err = models.ParseMoney(field("salary"), field("currency")); err != nil {
+			errs = append(errs, "salary: "+err.Error())
+		}
+		record.ParseError = strings.Join(errs, "; ")
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
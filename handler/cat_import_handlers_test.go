@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseCatJSONStopsAfterMaxRows(t *testing.T) {
+	body := `[` + strings.TrimSuffix(strings.Repeat(`{"name": "Tom", "breed": "Siamese", "salary": 1000},`, 10), ",") + `]`
+
+	records, err := parseCatJSON(strings.NewReader(body), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if records[2].Row != 3 || records[2].Cat.Name != "Tom" {
+		t.Fatalf("unexpected record %+v", records[2])
+	}
+}
+
+func TestParseCatJSONKeepsMalformedRows(t *testing.T) {
+	records, err := parseCatJSON(strings.NewReader(`[{"name": "Tom"}, {"salary": "abc"}]`), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].ParseError != "" || records[1].ParseError == "" {
+		t.Fatalf("expected only the second row to fail, got %+v", records)
+	}
+
+	if _, err := parseCatJSON(strings.NewReader(`{"name": "Tom"}`), 10); err == nil {
+		t.Fatal("expected an error for a body that is not an array")
+	}
+}
+
+func TestParseCatCSVStopsAfterMaxRows(t *testing.T) {
+	body := "name,breed,salary\n" + strings.Repeat("Tom,Siamese,1000\n", 10)
+
+	records, err := parseCatCSV(strings.NewReader(body), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+}
+
+func TestParseCatImportReportsBodyOverLimit(t *testing.T) {
+	jsonBody := `[` + strings.TrimSuffix(strings.Repeat(`{"name": "Tom", "breed": "Siamese", "salary": 1000},`, 100), ",") + `]`
+	csvBody := "name,breed,salary\n" + strings.Repeat("Tom,Siamese,1000\n", 100)
+
+	var tooLarge *http.MaxBytesError
+	if _, err := parseCatJSON(http.MaxBytesReader(nil, io.NopCloser(strings.NewReader(jsonBody)), 200), 1000); !errors.As(err, &tooLarge) {
+		t.Fatalf("JSON: err = %v, want *http.MaxBytesError", err)
+	}
+	if _, err := parseCatCSV(http.MaxBytesReader(nil, io.NopCloser(strings.NewReader(csvBody)), 200), 1000); !errors.As(err, &tooLarge) {
+		t.Fatalf("CSV: err = %v, want *http.MaxBytesError", err)
+	}
+}
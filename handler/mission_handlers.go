@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"net/http"
@@ -23,21 +24,30 @@ type MissionHandlerInterface interface {
 	DeleteMission(c echo.Context) error
 	UpdateMission(c echo.Context) error
 	CompleteMission(c echo.Context) error
+	TransitionMission(c echo.Context) error
 	AssignCatToMission(c echo.Context) error
+	UnassignCat(c echo.Context) error
+	HandoffMission(c echo.Context) error
+	GetAssignments(c echo.Context) error
 	ListMissions(c echo.Context) error
 	GetMission(c echo.Context) error
+	GetCandidates(c echo.Context) error
+	AutoAssign(c echo.Context) error
 }
 
 // CreateMission creates a new mission
 func (mh *MissionHandler) CreateMission(c echo.Context) error {
-	mission := new(models.Mission)
-	if err := c.Bind(mission); err != nil {
+	var requestBody struct {
+		models.Mission
+		models.AssignmentOptions
+	}
+	if err := c.Bind(&requestBody); err != nil {
 		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	createdMission, err, respStatus := mh.MissionService.CreateMission(mission)
+	createdMission, err, respStatus := mh.MissionService.CreateMission(&requestBody.Mission, requestBody.AssignmentOptions)
 	if err != nil {
-		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
 	}
 
 	return c.JSON(http.StatusCreated, response.UserResponse{Status: http.StatusCreated, Message: "success", Data: &echo.Map{"data": createdMission}})
@@ -81,9 +91,9 @@ func (mh *MissionHandler) CompleteMission(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
 	}
 
-	err = mh.MissionService.CompleteMission(id)
+	err, respStatus := mh.MissionService.CompleteMission(id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, response.UserResponse{Status: http.StatusInternalServerError, Message: "error", Data: &echo.Map{"data": err.Error()}})
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
 	}
 
 	resp := fmt.Sprintf("Mission %d marked as completed", id)
@@ -91,6 +101,26 @@ func (mh *MissionHandler) CompleteMission(c echo.Context) error {
 	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": resp}})
 }
 
+// TransitionMission moves a mission to another state of its lifecycle
+func (mh *MissionHandler) TransitionMission(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	var transition models.MissionTransition
+	if err := c.Bind(&transition); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
+	}
+
+	mission, err, respStatus := mh.MissionService.TransitionMission(id, transition.Status)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": mission}})
+}
+
 // AssignCatToMission assigns a cat to a mission
 func (mh *MissionHandler) AssignCatToMission(c echo.Context) error {
 	missionID, err := strconv.Atoi(c.Param("id"))
@@ -100,23 +130,81 @@ func (mh *MissionHandler) AssignCatToMission(c echo.Context) error {
 
 	var requestBody struct {
 		CatID int `json:"CatID"`
+		models.AssignmentOptions
 	}
 	if err := c.Bind(&requestBody); err != nil {
-		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
 	}
 
-	err, respStatus := mh.MissionService.AssignCatToMission(missionID, requestBody.CatID)
+	err, respStatus := mh.MissionService.AssignCatToMission(missionID, requestBody.CatID, requestBody.AssignmentOptions)
 	if err != nil {
-		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
 	}
 
 	resp := fmt.Sprintf("Mission %d assigned to Cat %d", missionID, requestBody.CatID)
 	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": resp}})
 }
 
-// ListMissions retrieves all missions
+// UnassignCat takes the cat off a mission, ?reason= is kept in the assignment history
+func (mh *MissionHandler) UnassignCat(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	err, respStatus := mh.MissionService.UnassignCat(id, c.QueryParam("reason"))
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	resp := fmt.Sprintf("Mission %d is back in the unassigned queue", id)
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": resp}})
+}
+
+// HandoffMission hands an active mission over to another cat, keeping its progress
+func (mh *MissionHandler) HandoffMission(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	var handoff models.MissionHandoff
+	if err := c.Bind(&handoff); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid request body"}})
+	}
+
+	mission, err, respStatus := mh.MissionService.HandoffMission(id, handoff)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": mission}})
+}
+
+// GetAssignments returns the assignment history of a mission
+func (mh *MissionHandler) GetAssignments(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	assignments, err, respStatus := mh.MissionService.GetAssignments(id)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": assignments}})
+}
+
+// ListMissions retrieves all missions, ?unassigned=true lists the queue of missions waiting for a cat
 func (mh *MissionHandler) ListMissions(c echo.Context) error {
-	missions, err := mh.MissionService.ListMissions()
+	unassigned, err := queryBoolPtr(c, "unassigned")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+	filter := models.MissionFilter{Unassigned: unassigned != nil && *unassigned}
+
+	missions, err := mh.MissionService.ListMissions(filter)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, response.UserResponse{Status: http.StatusInternalServerError, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
 	}
@@ -138,3 +226,50 @@ func (mh *MissionHandler) GetMission(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": mission}})
 }
+
+// GetCandidates ranks the cats that could take the mission, each with its score breakdown
+func (mh *MissionHandler) GetCandidates(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	candidates, err, respStatus := mh.MissionService.GetCandidates(id)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": candidates}})
+}
+
+// AutoAssign assigns the top ranked eligible candidate to the mission
+func (mh *MissionHandler) AutoAssign(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": "Invalid mission ID"}})
+	}
+
+	candidate, err, respStatus := mh.MissionService.AutoAssign(id)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: missionErrorData(err)})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": candidate}})
+}
+
+// missionErrorData adds the unmet requirements to the error response when a cat is ineligible
+// and the allowed next states when a transition is refused
+func missionErrorData(err error) *echo.Map {
+	data := echo.Map{"data": err.Error()}
+
+	var eligibilityErr *service.IneligibleCatError
+	if errors.As(err, &eligibilityErr) {
+		data["unmetRequirements"] = eligibilityErr.Unmet
+	}
+	var transitionErr *service.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		data["allowedTransitions"] = transitionErr.Allowed
+	}
+
+	return &data
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"spyCat/database/models"
+	"spyCat/response"
+	"spyCat/service"
+	"strconv"
+)
+
+type SkillHandler struct {
+	skillService service.SkillServiceInterface
+}
+
+func NewSkillHandler(service service.SkillServiceInterface) *SkillHandler {
+	return &SkillHandler{skillService: service}
+}
+
+type SkillHandlerInterface interface {
+	ListSkills(c echo.Context) error
+	CreateSkill(c echo.Context) error
+	ListCatSkills(c echo.Context) error
+	AddCatSkill(c echo.Context) error
+	UpdateCatSkill(c echo.Context) error
+	RemoveCatSkill(c echo.Context) error
+}
+
+// ListSkills lists the skills catalog
+func (sh *SkillHandler) ListSkills(c echo.Context) error {
+	skills, err, respStatus := sh.skillService.ListSkills()
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": skills}})
+}
+
+// CreateSkill adds a skill to the catalog
+func (sh *SkillHandler) CreateSkill(c echo.Context) error {
+	var skill models.Skill
+	if err := c.Bind(&skill); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	created, err, respStatus := sh.skillService.CreateSkill(skill)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusCreated, response.UserResponse{Status: http.StatusCreated, Message: "success", Data: &echo.Map{"data": created}})
+}
+
+// ListCatSkills lists the skills of a cat, best first
+func (sh *SkillHandler) ListCatSkills(c echo.Context) error {
+	catID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	catSkills, err, respStatus := sh.skillService.ListCatSkills(catID)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": catSkills}})
+}
+
+// AddCatSkill gives a cat a catalog skill with a proficiency level
+func (sh *SkillHandler) AddCatSkill(c echo.Context) error {
+	catID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	var catSkill models.CatSkill
+	if err := c.Bind(&catSkill); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	created, err, respStatus := sh.skillService.AddCatSkill(catID, catSkill)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusCreated, response.UserResponse{Status: http.StatusCreated, Message: "success", Data: &echo.Map{"data": created}})
+}
+
+// UpdateCatSkill changes the proficiency of one of the cat's skills
+func (sh *SkillHandler) UpdateCatSkill(c echo.Context) error {
+	catID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	skillID, err := strconv.Atoi(c.Param("skillId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	var requestBody struct {
+		Proficiency int `json:"Proficiency"`
+	}
+	if err := c.Bind(&requestBody); err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	updated, err, respStatus := sh.skillService.UpdateCatSkill(catID, skillID, requestBody.Proficiency)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": updated}})
+}
+
+// RemoveCatSkill removes a skill from a cat
+func (sh *SkillHandler) RemoveCatSkill(c echo.Context) error {
+	catID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	skillID, err := strconv.Atoi(c.Param("skillId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.UserResponse{Status: http.StatusBadRequest, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	err, respStatus := sh.skillService.RemoveCatSkill(catID, skillID)
+	if err != nil {
+		return c.JSON(respStatus, response.UserResponse{Status: respStatus, Message: "error", Data: &echo.Map{"data": err.Error()}})
+	}
+
+	resp := fmt.Sprintf("Skill %d removed from Cat %d", skillID, catID)
+	return c.JSON(http.StatusOK, response.UserResponse{Status: http.StatusOK, Message: "success", Data: &echo.Map{"data": resp}})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
 	"spyCat/middleware"
 	"spyCat/routes"
@@ -12,6 +13,7 @@ func main() {
 
 	middleware.UserAuth(e)
 	routes.UserRoute(e)
+	routes.StartWorkers(context.Background())
 
 	e.Logger.Fatal(e.Start(":6000"))
 }
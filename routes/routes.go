@@ -1,31 +1,65 @@
 package routes
 
 import (
+	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"spyCat/config"
 	"spyCat/database"
 	"spyCat/handler"
 	"spyCat/service"
 )
 
 var validate = validator.New()
-var catHandler = handler.NewCatHandler(service.NewCatService(database.NewCatDatabase(database.NewDatabase()), validate))
+var breedProvider, breedCache = newBreedProvider()
+var breedHandler = handler.NewBreedHandler(service.NewBreedService(breedProvider, breedCache))
+var catService = service.NewCatService(database.NewCatDatabase(database.NewDatabase()), validate, breedProvider, newPayrollPolicy())
+var catHandler = handler.NewCatHandler(catService)
+var skillHandler = handler.NewSkillHandler(service.NewSkillService(database.NewSkillDatabase(database.NewDatabase()), validate))
 var missionHandler = handler.NewMissionHandler(service.NewMissionService(database.NewMissionDatabase(database.NewDatabase()), validate))
 var targetHandler = handler.NewTargetHandler(service.NewTargetService(database.NewTargetDatabase(database.NewDatabase()), validate))
 
 func UserRoute(e *echo.Echo) {
 	e.POST("/cats", catHandler.CreateCat)
+	e.POST("/cats/import", catHandler.ImportCats)
+	e.GET("/cats/search", catHandler.SearchCats)
 	e.GET("/cats/:id", catHandler.GetCat)
 	e.GET("/cats", catHandler.GetAllCats)
 	e.PUT("/cats/:id", catHandler.UpdateCatSalary)
+	e.PATCH("/cats/:id", catHandler.UpdateCat)
+	e.GET("/cats/:id/salary-history", catHandler.GetSalaryHistory)
 	e.DELETE("/cats/:id", catHandler.DeleteCat)
+	e.POST("/cats/:id/restore", catHandler.RestoreCat)
+	e.GET("/cats/:id/availability", catHandler.GetCatAvailability)
+	e.GET("/cats/:id/rank", catHandler.GetCatRank)
+	e.GET("/cats/:id/skills", skillHandler.ListCatSkills)
+	e.POST("/cats/:id/skills", skillHandler.AddCatSkill)
+	e.PUT("/cats/:id/skills/:skillId", skillHandler.UpdateCatSkill)
+	e.DELETE("/cats/:id/skills/:skillId", skillHandler.RemoveCatSkill)
+
+	e.GET("/salary-changes", catHandler.ListSalaryChanges)
+	e.POST("/salary-changes/:id/approve", catHandler.ApproveSalaryChange)
+	e.POST("/salary-changes/:id/reject", catHandler.RejectSalaryChange)
+
+	e.GET("/skills", skillHandler.ListSkills)
+	e.POST("/skills", skillHandler.CreateSkill)
 
 	e.POST("/missions", missionHandler.CreateMission)
 	e.DELETE("/missions/:id", missionHandler.DeleteMission)
 	e.PUT("/missions/:id/complete", missionHandler.CompleteMission)
+	e.POST("/missions/:id/transitions", missionHandler.TransitionMission)
 	e.PUT("/missions/:id/assign", missionHandler.AssignCatToMission)
+	e.DELETE("/missions/:id/assignment", missionHandler.UnassignCat)
+	e.POST("/missions/:id/handoff", missionHandler.HandoffMission)
+	e.GET("/missions/:id/assignments", missionHandler.GetAssignments)
 	e.GET("/missions", missionHandler.ListMissions)
 	e.GET("/missions/:id", missionHandler.GetMission)
+	e.GET("/missions/:id/candidates", missionHandler.GetCandidates)
+	e.POST("/missions/:id/auto-assign", missionHandler.AutoAssign)
+
+	e.GET("/breeds", breedHandler.ListBreeds)
+	e.GET("/breeds/cache", breedHandler.GetCacheStats)
 
 	e.PUT("/targets", targetHandler.UpdateTarget)
 	e.PUT("/targets/:id/notes", targetHandler.UpdateTargetNotes)
@@ -34,3 +68,28 @@ func UserRoute(e *echo.Echo) {
 	e.POST("/missions/:missionId/targets", targetHandler.AddTarget)
 
 }
+
+// StartWorkers runs the background jobs until ctx is cancelled
+func StartWorkers(ctx context.Context) {
+	cfg := config.LoadENV(".env")
+	go service.NewBreedVerifier(catService, cfg.BreedVerifyInterval).Run(ctx)
+	go service.NewSalaryScheduler(catService, cfg.SalaryScheduleInterval).Run(ctx)
+	go service.NewRankPromoter(catService, cfg.RankPromotionInterval).Run(ctx)
+}
+
+func newBreedProvider() (service.BreedProvider, *service.BreedCache) {
+	provider, cache, err := service.NewBreedProvider(config.LoadENV(".env"))
+	if err != nil {
+		log.Panic().Err(err).Msg("Error configuring breed provider")
+	}
+	return provider, cache
+}
+
+func newPayrollPolicy() service.PayrollPolicy {
+	cfg := config.LoadENV(".env")
+	ranks, err := service.ParseRankLadder(cfg.Ranks, cfg.RankSalaryCurrency)
+	if err != nil {
+		log.Panic().Err(err).Msg("Error configuring ranks")
+	}
+	return service.PayrollPolicy{Ranks: ranks, RaiseApprovalThreshold: cfg.RaiseApprovalThreshold, RaiseApprovalWindow: cfg.RaiseApprovalWindow}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const breedRefreshRetryDelay = time.Minute
+
+type BreedCacheStats struct {
+	Hits            int64     `json:"Hits"`
+	StaleHits       int64     `json:"StaleHits"`
+	Misses          int64     `json:"Misses"`
+	Refreshes       int64     `json:"Refreshes"`
+	RefreshFailures int64     `json:"RefreshFailures"`
+	Size            int       `json:"Size"`
+	ExpiresAt       time.Time `json:"ExpiresAt"`
+}
+
+// BreedCache wraps an upstream BreedProvider. Concurrent refreshes are coalesced into one
+// upstream call, stale data keeps being served while a background refresh runs and the last
+// good catalog is persisted to disk so a restart does not need the network
+type BreedCache struct {
+	upstream BreedProvider
+	ttl      time.Duration
+	path     string
+
+	mu         sync.RWMutex
+	breeds     []CatBreed
+	expireAt   time.Time
+	refreshing chan struct{}
+	lastErr    error
+	nextRetry  time.Time
+
+	hits            atomic.Int64
+	staleHits       atomic.Int64
+	misses          atomic.Int64
+	refreshes       atomic.Int64
+	refreshFailures atomic.Int64
+}
+
+func NewBreedCache(upstream BreedProvider, ttl time.Duration, path string) *BreedCache {
+	bc := &BreedCache{upstream: upstream, ttl: ttl, path: path}
+	bc.loadFromDisk()
+	return bc
+}
+
+func (bc *BreedCache) Breeds() ([]CatBreed, error) {
+	bc.mu.Lock()
+	if len(bc.breeds) > 0 {
+		breeds := bc.breeds
+		if time.Now().Before(bc.expireAt) {
+			bc.mu.Unlock()
+			bc.hits.Add(1)
+			return breeds, nil
+		}
+
+		bc.startRefreshLocked()
+		bc.mu.Unlock()
+		bc.staleHits.Add(1)
+		return breeds, nil
+	}
+
+	bc.misses.Add(1)
+	if bc.refreshing == nil && bc.lastErr != nil && time.Now().Before(bc.nextRetry) {
+		// the upstream failed recently, do not make every caller wait for it again
+		err := bc.lastErr
+		bc.mu.Unlock()
+		return nil, err
+	}
+	done := bc.startRefreshLocked()
+	bc.mu.Unlock()
+
+	<-done
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if len(bc.breeds) == 0 {
+		if bc.lastErr != nil {
+			return nil, bc.lastErr
+		}
+		return nil, errors.New("breed catalog is empty")
+	}
+	return bc.breeds, nil
+}
+
+func (bc *BreedCache) Stats() BreedCacheStats {
+	bc.mu.RLock()
+	size, expireAt := len(bc.breeds), bc.expireAt
+	bc.mu.RUnlock()
+
+	return BreedCacheStats{
+		Hits:            bc.hits.Load(),
+		StaleHits:       bc.staleHits.Load(),
+		Misses:          bc.misses.Load(),
+		Refreshes:       bc.refreshes.Load(),
+		RefreshFailures: bc.refreshFailures.Load(),
+		Size:            size,
+		ExpiresAt:       expireAt,
+	}
+}
+
+// startRefreshLocked starts a refresh unless one is already running and returns a channel
+// closed when it finishes, bc.mu must be held
+func (bc *BreedCache) startRefreshLocked() <-chan struct{} {
+	if bc.refreshing != nil {
+		return bc.refreshing
+	}
+
+	done := make(chan struct{})
+	bc.refreshing = done
+	go bc.refresh(done)
+	return done
+}
+
+func (bc *BreedCache) refresh(done chan struct{}) {
+	bc.refreshes.Add(1)
+	breeds, err := bc.upstream.Breeds()
+	if err == nil && len(breeds) == 0 {
+		err = errors.New("breed provider returned an empty catalog")
+	}
+
+	bc.mu.Lock()
+	if err != nil {
+		bc.refreshFailures.Add(1)
+		bc.lastErr = err
+		// back off instead of retrying on every request, a stale catalog keeps being served meanwhile
+		retryIn := breedRefreshRetryDelay
+		if bc.ttl < retryIn {
+			retryIn = bc.ttl
+		}
+		bc.nextRetry = time.Now().Add(retryIn)
+		if len(bc.breeds) > 0 {
+			bc.expireAt = bc.nextRetry
+		}
+		log.Warn().Err(err).Msg("Failed to refresh breed catalog")
+	} else {
+		bc.breeds = breeds
+		bc.expireAt = time.Now().Add(bc.ttl)
+		bc.lastErr = nil
+	}
+	bc.refreshing = nil
+	bc.mu.Unlock()
+	close(done)
+
+	if err == nil {
+		bc.saveToDisk(breeds)
+	}
+}
+
+func (bc *BreedCache) loadFromDisk() {
+	if bc.path == "" {
+		return
+	}
+
+	info, err := os.Stat(bc.path)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(bc.path)
+	if err != nil {
+		log.Warn().Err(err).Msg("Unable to read persisted breed catalog")
+		return
+	}
+
+	var breeds []CatBreed
+	if err := json.Unmarshal(data, &breeds); err != nil {
+		log.Warn().Err(err).Msg("Unable to decode persisted breed catalog")
+		return
+	}
+
+	bc.breeds = breeds
+	bc.expireAt = info.ModTime().Add(bc.ttl)
+	log.Info().Msgf("Loaded %d breeds from %s", len(breeds), bc.path)
+}
+
+// saveToDisk writes to a temp file first so a crash never leaves a truncated catalog behind
+func (bc *BreedCache) saveToDisk(breeds []CatBreed) {
+	if bc.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(breeds)
+	if err != nil {
+		log.Warn().Err(err).Msg("Unable to encode breed catalog")
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(bc.path), filepath.Base(bc.path)+".*")
+	if err != nil {
+		log.Warn().Err(err).Msg("Unable to persist breed catalog")
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		log.Warn().Err(err).Msg("Unable to persist breed catalog")
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		log.Warn().Err(err).Msg("Unable to persist breed catalog")
+		return
+	}
+	if err := os.Rename(tmp.Name(), bc.path); err != nil {
+		log.Warn().Err(err).Msg("Unable to persist breed catalog")
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type failingBreedProvider struct {
+	calls atomic.Int64
+}
+
+func (p *failingBreedProvider) Breeds() ([]CatBreed, error) {
+	p.calls.Add(1)
+	return nil, errors.New("upstream is down")
+}
+
+func TestBreedCacheBacksOffWhenEmpty(t *testing.T) {
+	upstream := &failingBreedProvider{}
+	cache := NewBreedCache(upstream, time.Hour, "")
+
+	for i := 0; i < 5; i++ {
+		if _, err := cache.Breeds(); err == nil {
+			t.Fatalf("call %d: expected the upstream error", i)
+		}
+	}
+
+	if calls := upstream.calls.Load(); calls != 1 {
+		t.Fatalf("expected one upstream call during the backoff, got %d", calls)
+	}
+	if failures := cache.Stats().RefreshFailures; failures != 1 {
+		t.Fatalf("expected one refresh failure, got %d", failures)
+	}
+}
+
+func TestBreedCacheRetriesAfterBackoff(t *testing.T) {
+	upstream := &failingBreedProvider{}
+	cache := NewBreedCache(upstream, time.Hour, "")
+
+	if _, err := cache.Breeds(); err == nil {
+		t.Fatal("expected the upstream error")
+	}
+
+	cache.mu.Lock()
+	cache.nextRetry = time.Now().Add(-time.Second)
+	cache.mu.Unlock()
+
+	if _, err := cache.Breeds(); err == nil {
+		t.Fatal("expected the upstream error")
+	}
+	if calls := upstream.calls.Load(); calls != 2 {
+		t.Fatalf("expected a new upstream call once the backoff expired, got %d", calls)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/config"
+	"spyCat/database/models"
+	"strings"
+	"time"
+)
+
+type CatBreed struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Origin      string `json:"origin"`
+	Temperament string `json:"temperament"`
+	LifeSpan    string `json:"life_span"`
+}
+
+func (b CatBreed) Details() *models.Breed {
+	return &models.Breed{ID: b.ID, Name: b.Name, Origin: b.Origin, Temperament: b.Temperament, LifeSpan: b.LifeSpan}
+}
+
+// BreedProvider is a source of known cat breeds used to validate cat profiles
+type BreedProvider interface {
+	Breeds() ([]CatBreed, error)
+}
+
+const (
+	BreedProviderTheCatAPI = "thecatapi"
+	BreedProviderBundled   = "bundled"
+	BreedProviderChain     = "chain"
+)
+
+// NewBreedProvider builds the provider selected by BREED_PROVIDER,
+// "chain" asks TheCatAPI first and falls back to the bundled catalog.
+// TheCatAPI is always put behind a BreedCache, which is returned as well (nil for "bundled")
+func NewBreedProvider(cfg *config.Config) (BreedProvider, *BreedCache, error) {
+	switch strings.ToLower(cfg.BreedProvider) {
+	case BreedProviderTheCatAPI:
+		cache := NewBreedCache(NewTheCatAPIProvider(cfg.CatAPIURL), cfg.BreedCacheTTL, cfg.BreedCacheFile)
+		return cache, cache, nil
+	case BreedProviderBundled:
+		return NewBundledBreedProvider(), nil, nil
+	case BreedProviderChain, "":
+		cache := NewBreedCache(NewTheCatAPIProvider(cfg.CatAPIURL), cfg.BreedCacheTTL, cfg.BreedCacheFile)
+		return NewChainBreedProvider(cache, NewBundledBreedProvider()), cache, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown breed provider %q", cfg.BreedProvider)
+	}
+}
+
+type TheCatAPIProvider struct {
+	url    string
+	client *http.Client
+}
+
+func NewTheCatAPIProvider(url string) *TheCatAPIProvider {
+	return &TheCatAPIProvider{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *TheCatAPIProvider) Breeds() ([]CatBreed, error) {
+	req, err := http.NewRequest("GET", p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("failed to fetch cat breeds")
+	}
+
+	var breeds []CatBreed
+	if err := json.NewDecoder(resp.Body).Decode(&breeds); err != nil {
+		return nil, err
+	}
+
+	return breeds, nil
+}
+
+//go:embed data/breeds.json
+var bundledBreedsJSON []byte
+
+// BundledBreedProvider serves the breed catalog compiled into the binary, it never needs the network
+type BundledBreedProvider struct {
+	breeds []CatBreed
+	err    error
+}
+
+func NewBundledBreedProvider() *BundledBreedProvider {
+	var breeds []CatBreed
+	err := json.Unmarshal(bundledBreedsJSON, &breeds)
+	return &BundledBreedProvider{breeds: breeds, err: err}
+}
+
+func (p *BundledBreedProvider) Breeds() ([]CatBreed, error) {
+	return p.breeds, p.err
+}
+
+// sourcedBreedProvider is implemented by providers that can fall back to an offline catalog,
+// offline reports whether the catalog came from such a fallback
+type sourcedBreedProvider interface {
+	SourcedBreeds() (breeds []CatBreed, offline bool, err error)
+}
+
+// ChainBreedProvider returns the catalog of the first provider that succeeds,
+// catalogs from the providers after the first one are fallbacks
+type ChainBreedProvider struct {
+	providers []BreedProvider
+}
+
+func NewChainBreedProvider(providers ...BreedProvider) *ChainBreedProvider {
+	return &ChainBreedProvider{providers: providers}
+}
+
+func (p *ChainBreedProvider) Breeds() ([]CatBreed, error) {
+	breeds, _, err := p.SourcedBreeds()
+	return breeds, err
+}
+
+func (p *ChainBreedProvider) SourcedBreeds() ([]CatBreed, bool, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		breeds, err := provider.Breeds()
+		if err == nil && len(breeds) > 0 {
+			return breeds, i > 0, nil
+		}
+		if err == nil {
+			err = errors.New("breed provider returned an empty catalog")
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, false, fmt.Errorf("no breed provider available: %w", errors.Join(errs...))
+}
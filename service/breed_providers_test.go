@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+type staticBreedProvider struct {
+	breeds []CatBreed
+	err    error
+}
+
+func (p staticBreedProvider) Breeds() ([]CatBreed, error) {
+	return p.breeds, p.err
+}
+
+func TestChainBreedProviderReportsFallback(t *testing.T) {
+	catalog := []CatBreed{{ID: "abys", Name: "Abyssinian"}}
+	down := staticBreedProvider{err: errors.New("upstream is down")}
+	up := staticBreedProvider{breeds: catalog}
+
+	tests := []struct {
+		name        string
+		providers   []BreedProvider
+		wantOffline bool
+		wantErr     bool
+	}{
+		{name: "primary answers", providers: []BreedProvider{up, up}},
+		{name: "fallback answers", providers: []BreedProvider{down, up}, wantOffline: true},
+		{name: "empty primary", providers: []BreedProvider{staticBreedProvider{}, up}, wantOffline: true},
+		{name: "nothing answers", providers: []BreedProvider{down, down}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breeds, offline, err := NewChainBreedProvider(tt.providers...).SourcedBreeds()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if offline != tt.wantOffline {
+				t.Fatalf("offline = %v, want %v", offline, tt.wantOffline)
+			}
+			if !tt.wantErr && len(breeds) != len(catalog) {
+				t.Fatalf("got %d breeds, want %d", len(breeds), len(catalog))
+			}
+		})
+	}
+}
+
+func TestLoadBreedsTreatsFallbackAsUnverified(t *testing.T) {
+	catalog := []CatBreed{{ID: "abys", Name: "Abyssinian"}}
+	cs := &CatService{breeds: NewChainBreedProvider(staticBreedProvider{err: errors.New("down")}, staticBreedProvider{breeds: catalog})}
+
+	breeds, err := cs.loadBreeds()
+	if !errors.Is(err, ErrBreedSourceUnavailable) {
+		t.Fatalf("err = %v, want ErrBreedSourceUnavailable", err)
+	}
+	if len(breeds) != 1 {
+		t.Fatalf("expected the offline catalog to be returned, got %d breeds", len(breeds))
+	}
+}
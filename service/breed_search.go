@@ -0,0 +1,148 @@
+package service
+
+import (
+	"sort"
+	"strings"
+)
+
+const maxBreedSuggestions = 3
+
+type breedMatch struct {
+	breed CatBreed
+	rank  int
+	dist  int
+}
+
+// searchBreeds ranks breeds for autocomplete: name prefix first, then a word prefix,
+// then a substring and finally names within a small edit distance of the query
+func searchBreeds(breeds []CatBreed, query string) []CatBreed {
+	q := normalizeBreed(query)
+	if q == "" {
+		return breeds
+	}
+
+	var matches []breedMatch
+	for _, breed := range breeds {
+		name := normalizeBreed(breed.Name)
+		switch {
+		case strings.HasPrefix(name, q):
+			matches = append(matches, breedMatch{breed: breed, rank: 0})
+		case hasWordPrefix(name, q):
+			matches = append(matches, breedMatch{breed: breed, rank: 1})
+		case strings.Contains(name, q):
+			matches = append(matches, breedMatch{breed: breed, rank: 2})
+		default:
+			if dist := prefixDistance(name, q); dist <= fuzzyTolerance(q) {
+				matches = append(matches, breedMatch{breed: breed, rank: 3, dist: dist})
+			}
+		}
+	}
+
+	sortBreedMatches(matches)
+
+	result := make([]CatBreed, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.breed)
+	}
+	return result
+}
+
+// suggestBreeds returns the closest catalog names for a breed that failed validation
+func suggestBreeds(breeds []CatBreed, input string) []string {
+	in := normalizeBreed(input)
+	if in == "" {
+		return nil
+	}
+
+	var matches []breedMatch
+	for _, breed := range breeds {
+		name := normalizeBreed(breed.Name)
+		dist := levenshtein(name, in)
+		switch {
+		case strings.HasPrefix(name, in):
+			matches = append(matches, breedMatch{breed: breed, rank: 0, dist: dist})
+		case dist <= fuzzyTolerance(in) || strings.Contains(name, in) || strings.Contains(in, name):
+			matches = append(matches, breedMatch{breed: breed, rank: 1, dist: dist})
+		}
+	}
+
+	sortBreedMatches(matches)
+
+	var suggestions []string
+	for i := 0; i < len(matches) && i < maxBreedSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].breed.Name)
+	}
+	return suggestions
+}
+
+func sortBreedMatches(matches []breedMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].breed.Name < matches[j].breed.Name
+	})
+}
+
+func normalizeBreed(breed string) string {
+	return strings.ToLower(strings.TrimSpace(breed))
+}
+
+func hasWordPrefix(name, prefix string) bool {
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == ' ' || r == '-' }) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyTolerance allows one typo per four characters, so short queries stay strict
+func fuzzyTolerance(s string) int {
+	return len([]rune(s))/4 + 1
+}
+
+// prefixDistance compares the query with the start of the name so partially typed names still match
+func prefixDistance(name, query string) int {
+	n := []rune(name)
+	if len(n) > len([]rune(query)) {
+		n = n[:len([]rune(query))]
+	}
+	return levenshtein(string(n), query)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type BreedServiceInterface interface {
+	ListBreeds(query string) ([]CatBreed, error, int)
+	CacheStats() BreedCacheStats
+}
+
+type BreedService struct {
+	breeds BreedProvider
+	cache  *BreedCache
+}
+
+func NewBreedService(breeds BreedProvider, cache *BreedCache) *BreedService {
+	return &BreedService{breeds: breeds, cache: cache}
+}
+
+// ListBreeds returns the whole catalog, or the breeds matching query ranked for autocomplete
+func (bs *BreedService) ListBreeds(query string) ([]CatBreed, error, int) {
+	breeds, err := bs.breeds.Breeds()
+	if err != nil {
+		return nil, fmt.Errorf("breed catalog is unavailable: %w", err), http.StatusServiceUnavailable
+	}
+
+	return searchBreeds(breeds, query), nil, http.StatusOK
+}
+
+// CacheStats reports the breed cache counters, all zero when the provider is not cached
+func (bs *BreedService) CacheStats() BreedCacheStats {
+	if bs.cache == nil {
+		return BreedCacheStats{}
+	}
+	return bs.cache.Stats()
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// BreedVerifier periodically settles cats whose breed could not be checked when they were saved
+type BreedVerifier struct {
+	cats     *CatService
+	interval time.Duration
+}
+
+func NewBreedVerifier(cats *CatService, interval time.Duration) *BreedVerifier {
+	return &BreedVerifier{cats: cats, interval: interval}
+}
+
+// Run blocks until ctx is cancelled
+func (bv *BreedVerifier) Run(ctx context.Context) {
+	runEvery(ctx, bv.interval, bv.verify)
+}
+
+func (bv *BreedVerifier) verify() {
+	verified, rejected, err := bv.cats.VerifyPendingBreeds()
+	if errors.Is(err, ErrBreedSourceUnavailable) {
+		log.Info().Msg("Breed source still unavailable, pending breeds will be re-checked later")
+	} else if err != nil {
+		log.Warn().Err(err).Msg("Failed to verify pending breeds")
+	}
+
+	if verified > 0 || rejected > 0 {
+		log.Info().Msgf("Breed verification: %d verified, %d rejected", verified, rejected)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"spyCat/database"
+	"spyCat/database/models"
+	"testing"
+)
+
+// deletionCatDatabase keeps the deletion it was asked to make
+type deletionCatDatabase struct {
+	database.CatDatabaseInterface
+	cats     map[int]models.Cat
+	missions []models.Mission
+	deleted  *models.CatDeletion
+}
+
+func (db *deletionCatDatabase) SelectByID(id int) (*models.Cat, error) {
+	cat, ok := db.cats[id]
+	if !ok {
+		return nil, errors.New("no such cat")
+	}
+	return &cat, nil
+}
+
+func (db *deletionCatDatabase) SelectActiveMissions(catID int) ([]models.Mission, error) {
+	return db.missions, nil
+}
+
+func (db *deletionCatDatabase) Delete(catID int, deletion models.CatDeletion) ([]int, error) {
+	db.deleted = &deletion
+	ids := make([]int, len(db.missions))
+	for i, mission := range db.missions {
+		ids[i] = mission.ID
+	}
+	return ids, nil
+}
+
+func TestDeleteCatChecksReplacementEligibility(t *testing.T) {
+	five := 5
+	cats := map[int]models.Cat{
+		1: {ID: 1, Name: "Archived soon", YearsOfExperience: 8},
+		2: {ID: 2, Name: "Junior", YearsOfExperience: 2},
+	}
+	demanding := []models.Mission{{ID: 10, Requirements: &models.MissionRequirements{MinExperience: &five}}}
+
+	tests := []struct {
+		name         string
+		missions     []models.Mission
+		deletion     models.CatDeletion
+		wantStatus   int
+		wantOverride bool
+	}{
+		{"no requirements", []models.Mission{{ID: 10}}, models.CatDeletion{}, http.StatusOK, false},
+		{"requirements not met", demanding, models.CatDeletion{}, http.StatusUnprocessableEntity, false},
+		{"override without a reason", demanding, models.CatDeletion{OverrideEligibility: true}, http.StatusBadRequest, false},
+		{"override with a reason", demanding, models.CatDeletion{OverrideEligibility: true, OverrideReason: "nobody else"}, http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &deletionCatDatabase{cats: cats, missions: tt.missions}
+			cs := NewCatService(db, validator.New(), staticBreedProvider{}, PayrollPolicy{})
+			tt.deletion.Mode = models.CatDeletionReassign
+			tt.deletion.ReplacementCatID = 2
+
+			_, err, status := cs.DeleteCat(1, tt.deletion)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			if status != http.StatusOK {
+				if db.deleted != nil {
+					t.Fatal("the cat was archived despite the error")
+				}
+				return
+			}
+			if override := db.deleted.Override; (override != nil) != tt.wantOverride {
+				t.Fatalf("override = %+v, want one: %v", override, tt.wantOverride)
+			} else if override != nil && (override.CatID != 2 || len(override.UnmetRequirements) != 1) {
+				t.Fatalf("unexpected override %+v", override)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+)
+
+const (
+	// MaxCatImportRows caps a single import so one request cannot hold a transaction open for too long
+	MaxCatImportRows = 1000
+	// MaxCatImportBytes caps the request body of an import, far above what MaxCatImportRows cats take
+	MaxCatImportBytes = 4 << 20
+)
+
+// ImportCats validates every record like CreateCat, against a single load of the breed catalog,
+// and inserts the valid ones according to the mode. The report is returned with errors too
+func (cs *CatService) ImportCats(records []models.CatImportRecord, mode models.CatImportMode) (*models.CatImportReport, error, int) {
+	if mode == "" {
+		mode = models.CatImportAtomic
+	}
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid import mode %q, use %s or %s", mode, models.CatImportAtomic, models.CatImportBestEffort), http.StatusBadRequest
+	}
+	if len(records) == 0 {
+		return nil, errors.New("the import contains no cats"), http.StatusBadRequest
+	}
+	if len(records) > MaxCatImportRows {
+		return nil, fmt.Errorf("an import is limited to %d cats, split the file", MaxCatImportRows), http.StatusBadRequest
+	}
+
+	report := &models.CatImportReport{Mode: mode, Total: len(records), Rows: make([]models.CatImportRow, len(records))}
+	cats := make([]models.Cat, len(records))
+	valid := make([]int, 0, len(records))
+
+	breeds, breedsErr := cs.loadBreeds()
+	for i, record := range records {
+		row := &report.Rows[i]
+		row.Row = record.Row
+		row.Name = record.Cat.Name
+
+		if record.ParseError != "" {
+			row.Errors = append(row.Errors, record.ParseError)
+			continue
+		}
+
+		cat, err := cs.prepareCat(record.Cat, breeds, breedsErr)
+		if err != nil {
+			row.Errors = append(row.Errors, err.Error())
+			continue
+		}
+		cats[i] = cat
+		row.BreedVerification = cat.BreedVerification
+		valid = append(valid, i)
+	}
+	report.Failed = len(records) - len(valid)
+
+	if mode == models.CatImportAtomic {
+		if report.Failed > 0 {
+			for _, i := range valid {
+				report.Rows[i].BreedVerification = ""
+			}
+			return report, fmt.Errorf("%d of %d rows are invalid, nothing was imported", report.Failed, report.Total), http.StatusUnprocessableEntity
+		}
+
+		ids, failed, err := cs.DbCat.InsertMany(cats)
+		if failed >= 0 {
+			for i := range report.Rows {
+				report.Rows[i].BreedVerification = ""
+			}
+			report.Rows[failed].Errors = append(report.Rows[failed].Errors, err.Error())
+			report.Failed = 1
+			return report, fmt.Errorf("row %d could not be saved, nothing was imported", report.Rows[failed].Row), http.StatusUnprocessableEntity
+		}
+		if err != nil {
+			return nil, err, http.StatusInternalServerError
+		}
+		for i, id := range ids {
+			report.Rows[i].CatID = id
+		}
+		report.Created = len(ids)
+		return report, nil, http.StatusCreated
+	}
+
+	for _, i := range valid {
+		id, err := cs.DbCat.Insert(cats[i])
+		if err != nil {
+			report.Rows[i].BreedVerification = ""
+			report.Rows[i].Errors = append(report.Rows[i].Errors, err.Error())
+			report.Failed++
+			continue
+		}
+		report.Rows[i].CatID = id
+		report.Created++
+	}
+
+	switch {
+	case report.Created == 0:
+		return report, errors.New("no row could be imported"), http.StatusUnprocessableEntity
+	case report.Failed > 0:
+		return report, nil, http.StatusMultiStatus
+	}
+	return report, nil, http.StatusCreated
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"net/http"
+	"spyCat/database"
+	"spyCat/database/models"
+	"strings"
+	"testing"
+)
+
+// importCatDatabase refuses the cat at index failAt like the database would
+type importCatDatabase struct {
+	database.CatDatabaseInterface
+	failAt int
+}
+
+func (db importCatDatabase) InsertMany(cats []models.Cat) ([]int, int, error) {
+	if db.failAt >= 0 {
+		return nil, db.failAt, &pq.Error{Code: "23514", Message: "new row violates check constraint"}
+	}
+	ids := make([]int, len(cats))
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids, -1, nil
+}
+
+func TestImportCatsAtomic(t *testing.T) {
+	breeds := staticBreedProvider{breeds: []CatBreed{{ID: "siam", Name: "Siamese"}}}
+	cat := func(name string) models.CatImportRecord {
+		return models.CatImportRecord{Cat: models.Cat{Name: name, Breed: "Siamese", YearsOfExperience: 2, Salary: usd(100000)}}
+	}
+
+	tests := []struct {
+		name       string
+		names      []string
+		failAt     int
+		wantStatus int
+		wantRowErr int
+	}{
+		{"all saved", []string{"Tom", "Kitty"}, -1, http.StatusCreated, -1},
+		{"name too long for the column", []string{"Tom", strings.Repeat("x", 101)}, -1, http.StatusUnprocessableEntity, 1},
+		{"database refuses a row", []string{"Tom", "Kitty", "Felix"}, 2, http.StatusUnprocessableEntity, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewCatService(importCatDatabase{failAt: tt.failAt}, validator.New(), breeds, PayrollPolicy{})
+			records := make([]models.CatImportRecord, len(tt.names))
+			for i, name := range tt.names {
+				records[i] = cat(name)
+				records[i].Row = i + 1
+			}
+
+			report, err, status := cs.ImportCats(records, models.CatImportAtomic)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			if report == nil {
+				t.Fatal("expected a report")
+			}
+			for i, row := range report.Rows {
+				if hasErr := len(row.Errors) > 0; hasErr != (i == tt.wantRowErr) {
+					t.Errorf("row %d errors = %v", row.Row, row.Errors)
+				}
+			}
+			if tt.wantRowErr >= 0 && (report.Created != 0 || report.Failed != 1) {
+				t.Errorf("created %d and failed %d, want 0 and 1", report.Created, report.Failed)
+			}
+		})
+	}
+}
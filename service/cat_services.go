@@ -2,8 +2,8 @@ package service
 
 import (
 	"database/sql"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"net/http"
 	"spyCat/database"
@@ -15,54 +15,120 @@ import (
 type CatService struct {
 	DbCat    database.CatDatabaseInterface
 	validate *validator.Validate
+	breeds   BreedProvider
+	payroll  PayrollPolicy
 }
 
-func NewCatService(DbCat database.CatDatabaseInterface, validate *validator.Validate) *CatService {
-	return &CatService{DbCat: DbCat, validate: validate}
+func NewCatService(DbCat database.CatDatabaseInterface, validate *validator.Validate, breeds BreedProvider, payroll PayrollPolicy) *CatService {
+	return &CatService{DbCat: DbCat, validate: validate, breeds: breeds, payroll: payroll}
 }
 
 type CatServiceInterface interface {
-	GetAllCats() (*[]models.Cat, error)
+	GetAllCats(filter models.CatFilter) (*models.CatList, error, int)
 	GetCat(catID int) (*models.Cat, error, int)
-	CreateCat(cat models.Cat) (int, error, int)
-	EditCatSalary(ID int, salary float64) (error, int)
-	DeleteCat(catID int) (error, int)
+	CreateCat(cat models.Cat, strict bool) (*models.CreatedCat, error, int)
+	EditCatSalary(ID int, change models.SalaryChange) (*models.SalaryChange, error, int)
+	GetSalaryHistory(catID int) ([]models.SalaryChange, error, int)
+	EditCat(ID int, patch models.CatPatch) (*models.Cat, error, int)
+	DeleteCat(catID int, deletion models.CatDeletion) ([]int, error, int)
+	RestoreCat(catID int) (*models.Cat, error, int)
+	GetCatAvailability(catID int) (*models.CatAvailability, error, int)
 	CatValidation(cat models.Cat) error
+	ImportCats(records []models.CatImportRecord, mode models.CatImportMode) (*models.CatImportReport, error, int)
+	SearchCats(query string, limit int) ([]models.CatMatch, error, int)
+	GetCatRank(catID int) (*models.CatRank, error, int)
+	ListSalaryChanges(status models.SalaryChangeStatus) ([]models.SalaryChange, error, int)
+	ReviewSalaryChange(id int, approve bool, review models.SalaryReview) (*models.SalaryChange, error, int)
 }
 
-type CatBreed struct {
-	Name string `json:"name"`
+// ErrBreedSourceUnavailable means the breed catalog could not be reached, the breed is neither valid nor invalid yet
+var ErrBreedSourceUnavailable = errors.New("breed source is unavailable")
+
+// InvalidBreedError is returned when a breed is not in the catalog, Suggestions holds the closest known names
+type InvalidBreedError struct {
+	Breed       string
+	Suggestions []string
 }
 
-var (
-	catAPIURL     = "https://api.thecatapi.com/v1/breeds"
-	httpClient    = &http.Client{Timeout: 10 * time.Second}
-	cachedBreeds  []CatBreed
-	cacheExpireAt time.Time
-)
+func (e *InvalidBreedError) Error() string {
+	msg := "invalid breed: the provided breed does not match any known cat breeds"
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean: %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// DuplicateCatError is returned in strict mode when cats looking like the new one already exist
+type DuplicateCatError struct {
+	Matches []models.CatMatch
+}
+
+func (e *DuplicateCatError) Error() string {
+	return fmt.Sprintf("%d cat(s) with a similar name and the same breed already exist", len(e.Matches))
+}
+
+// CreateCat creates the cat and reports existing cats that look like it, in strict mode those refuse the creation
+func (cs *CatService) CreateCat(cat models.Cat, strict bool) (*models.CreatedCat, error, int) {
+	breeds, breedsErr := cs.loadBreeds()
+	newCat, err := cs.prepareCat(cat, breeds, breedsErr)
+	if err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+
+	duplicates, err := cs.DbCat.SelectSimilar(newCat.Name, newCat.Breed)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if strict && len(duplicates) > 0 {
+		return nil, &DuplicateCatError{Matches: duplicates}, http.StatusConflict
+	}
 
-func (cs *CatService) CreateCat(cat models.Cat) (int, error, int) {
-	if !isValidBreed(cat.Breed) {
-		return 0, errors.New("invalid breed: the provided breed does not match any known cat breeds"), http.StatusBadRequest
+	insertedId, err := cs.DbCat.Insert(newCat)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
 	}
 
+	created := &models.CreatedCat{ID: insertedId, BreedVerification: newCat.BreedVerification, PossibleDuplicates: duplicates}
+	if newCat.BreedVerification == models.BreedVerificationPending {
+		return created, nil, http.StatusAccepted
+	}
+	return created, nil, http.StatusCreated
+}
+
+// prepareCat matches the breed against the loaded catalog and validates the new cat.
+// When the breed source is unavailable the cat is accepted with a pending breed, named after
+// the offline catalog entry when there is one
+func (cs *CatService) prepareCat(cat models.Cat, breeds []CatBreed, breedsErr error) (models.Cat, error) {
 	newCat := models.Cat{
 		Name:              cat.Name,
 		YearsOfExperience: cat.YearsOfExperience,
-		Breed:             cat.Breed,
+		HireDate:          cat.HireDate,
+		PriorExperience:   cat.PriorExperience,
+		Breed:             strings.TrimSpace(cat.Breed),
 		Salary:            cat.Salary,
+		BreedVerification: models.BreedVerificationVerified,
 	}
 
-	if err := cs.CatValidation(newCat); err != nil {
-		return 0, err, http.StatusBadRequest
+	if breedsErr != nil {
+		// accept the cat and let the breed verifier settle it once the source is back
+		newCat.BreedVerification = models.BreedVerificationPending
+		if breed, err := findBreed(breeds, cat.Breed); err == nil {
+			newCat.Breed = breed.Name
+			newCat.BreedDetails = breed.Details()
+		}
+	} else {
+		breed, err := findBreed(breeds, cat.Breed)
+		if err != nil {
+			return newCat, err
+		}
+		newCat.Breed = breed.Name
+		newCat.BreedDetails = breed.Details()
 	}
 
-	insertedId, err := cs.DbCat.Insert(newCat)
-	if err != nil {
-		return insertedId, err, http.StatusInternalServerError
+	if err := cs.CatValidation(newCat); err != nil {
+		return newCat, err
 	}
-
-	return insertedId, err, http.StatusCreated
+	return newCat, nil
 }
 
 func (cs *CatService) GetCat(catID int) (*models.Cat, error, int) {
@@ -76,88 +142,462 @@ func (cs *CatService) GetCat(catID int) (*models.Cat, error, int) {
 	return cat, nil, http.StatusOK
 }
 
-func (cs *CatService) EditCatSalary(ID int, salary float64) (error, int) {
-	err := cs.DbCat.Update(ID, salary)
+// EditCatSalary applies the change right away, or schedules it when the effective date is in the future
+func (cs *CatService) EditCatSalary(ID int, change models.SalaryChange) (*models.SalaryChange, error, int) {
+	if change.NewSalary.IsZero() {
+		return nil, errors.New("salary must be greater than zero"), http.StatusBadRequest
+	}
+
+	today := time.Now().Format(models.DateLayout)
+	if change.EffectiveDate == "" {
+		change.EffectiveDate = today
+	}
+	if _, err := time.Parse(models.DateLayout, change.EffectiveDate); err != nil {
+		return nil, errors.New("effective_date must be a date in YYYY-MM-DD format"), http.StatusBadRequest
+	}
+
+	cat, err := cs.DbCat.SelectByID(ID)
 	if errors.Is(err, sql.ErrNoRows) {
-		return errors.New("there is no user with that ID"), http.StatusBadRequest
+		return nil, errors.New("there is no user with that ID"), http.StatusBadRequest
 	} else if err != nil {
-		return err, http.StatusInternalServerError
+		return nil, err, http.StatusInternalServerError
+	}
+	if cat.IsArchived() {
+		return nil, errors.New("the cat is archived, restore it before changing its salary"), http.StatusConflict
+	}
+	if err, respStatus := cs.checkSalaryBand(ID, change.NewSalary); err != nil {
+		return nil, err, respStatus
+	}
+
+	change.CatID = ID
+	needsApproval, err := cs.needsApproval(cat, change.NewSalary)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if needsApproval {
+		change.Status = models.SalaryChangePending
+		if err := cs.DbCat.ScheduleSalaryChange(&change); err != nil {
+			return nil, err, http.StatusInternalServerError
+		}
+		return &change, nil, http.StatusAccepted
+	}
+	if change.EffectiveDate > today {
+		if err := cs.DbCat.ScheduleSalaryChange(&change); err != nil {
+			return nil, err, http.StatusInternalServerError
+		}
+		return &change, nil, http.StatusAccepted
+	}
+
+	err = cs.DbCat.Update(&change)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no user with that ID"), http.StatusBadRequest
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
 	}
 
-	return err, http.StatusOK
+	return &change, nil, http.StatusOK
 }
 
-func (cs *CatService) GetAllCats() (*[]models.Cat, error) {
-	cats, err := cs.DbCat.SelectAll()
+func (cs *CatService) GetSalaryHistory(catID int) ([]models.SalaryChange, error, int) {
+	if _, err := cs.DbCat.SelectByID(catID); errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	history, err := cs.DbCat.SelectSalaryHistory(catID)
 	if err != nil {
-		return nil, err
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return history, nil, http.StatusOK
+}
+
+// ApplyScheduledSalaryChanges applies future-dated raises whose effective date has arrived
+func (cs *CatService) ApplyScheduledSalaryChanges() (int, error) {
+	return cs.DbCat.ApplyDueSalaryChanges()
+}
+
+func (cs *CatService) EditCat(ID int, patch models.CatPatch) (*models.Cat, error, int) {
+	if patch.IsEmpty() {
+		return nil, errors.New("nothing to update: provide at least one field"), http.StatusBadRequest
+	}
+
+	cat, err := cs.DbCat.SelectByID(ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if cat.IsArchived() {
+		return nil, errors.New("the cat is archived, restore it before editing its profile"), http.StatusConflict
+	}
+
+	if err := cs.CatValidation(patch.Apply(*cat)); err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+
+	if patch.Breed != nil && strings.EqualFold(strings.TrimSpace(*patch.Breed), cat.Breed) {
+		patch.Breed = nil
+	}
+	if patch.Salary != nil && patch.Salary.Equal(cat.Salary) {
+		patch.Salary = nil
+	}
+	if patch.Salary != nil {
+		if err, respStatus := cs.checkSalaryBand(ID, *patch.Salary); err != nil {
+			return nil, err, respStatus
+		}
+		needsApproval, err := cs.needsApproval(cat, *patch.Salary)
+		if err != nil {
+			return nil, err, http.StatusInternalServerError
+		}
+		if needsApproval {
+			return nil, fmt.Errorf("raises above %g%% need supervisor approval, request it with PUT /cats/%d", cs.payroll.RaiseApprovalThreshold, ID), http.StatusUnprocessableEntity
+		}
+	}
+	if patch.Breed != nil {
+		verification := models.BreedVerificationVerified
+		breed, err := cs.matchBreed(*patch.Breed)
+		switch {
+		case errors.Is(err, ErrBreedSourceUnavailable):
+			trimmed := strings.TrimSpace(*patch.Breed)
+			patch.Breed = &trimmed
+			verification = models.BreedVerificationPending
+		case err != nil:
+			return nil, err, http.StatusBadRequest
+		default:
+			patch.Breed = &breed.Name
+			patch.BreedDetails = breed.Details()
+		}
+		patch.BreedVerification = &verification
+	}
+
+	updatedCat, err := cs.DbCat.UpdateProfile(ID, patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return updatedCat, nil, http.StatusOK
+}
+
+const (
+	defaultCatPageSize = 50
+	maxCatPageSize     = 500
+)
+
+func (cs *CatService) GetAllCats(filter models.CatFilter) (*models.CatList, error, int) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultCatPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxCatPageSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxCatPageSize), http.StatusBadRequest
+	}
+	if filter.Offset < 0 {
+		return nil, errors.New("offset cannot be negative"), http.StatusBadRequest
+	}
+	if filter.BreedVerification != "" && !filter.BreedVerification.IsValid() {
+		return nil, fmt.Errorf("unknown breed_verification %q", filter.BreedVerification), http.StatusBadRequest
+	}
+	if p := filter.MinProficiency; p != nil && (*p < models.MinProficiency || *p > models.MaxProficiency) {
+		return nil, fmt.Errorf("min_proficiency must be between %d and %d", models.MinProficiency, models.MaxProficiency), http.StatusBadRequest
+	}
+	if filter.SortBy != "" && !database.IsValidCatSort(filter.SortBy) {
+		return nil, fmt.Errorf("cannot sort cats by %q", filter.SortBy), http.StatusBadRequest
+	}
+	if filter.MinExperience != nil && filter.MaxExperience != nil && *filter.MinExperience > *filter.MaxExperience {
+		return nil, errors.New("min_experience cannot be greater than max_experience"), http.StatusBadRequest
+	}
+	if filter.MinSalary != nil && filter.MaxSalary != nil && filter.MinSalary.Cents > filter.MaxSalary.Cents {
+		return nil, errors.New("min_salary cannot be greater than max_salary"), http.StatusBadRequest
+	}
+
+	cats, total, err := cs.DbCat.SelectAll(filter)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
 	}
 
-	return cats, err
+	return &models.CatList{Cats: cats, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil, http.StatusOK
+}
+
+// ActiveMissionsError is returned when a cat cannot be archived because it is still on missions
+type ActiveMissionsError struct {
+	MissionIDs []int
 }
 
-func (cs *CatService) DeleteCat(catID int) (error, int) {
-	err := cs.DbCat.Delete(catID)
+func (e *ActiveMissionsError) Error() string {
+	return fmt.Sprintf("the cat is on %d active mission(s), reassign them with ?mode=reassign&replacement_id=<cat ID> or release them with ?mode=unassign", len(e.MissionIDs))
+}
+
+// DeleteCat archives the cat, it disappears from listings but keeps its mission history.
+// It returns the IDs of the active missions that were reassigned or unassigned on the way
+func (cs *CatService) DeleteCat(catID int, deletion models.CatDeletion) ([]int, error, int) {
+	switch deletion.Mode {
+	case models.CatDeletionRefuse, models.CatDeletionUnassign:
+	case models.CatDeletionReassign:
+		if deletion.ReplacementCatID == 0 {
+			return nil, errors.New("replacement_id is required to reassign missions"), http.StatusBadRequest
+		}
+		if deletion.ReplacementCatID == catID {
+			return nil, errors.New("a cat cannot replace itself"), http.StatusBadRequest
+		}
+	default:
+		return nil, fmt.Errorf("unknown deletion mode %q", deletion.Mode), http.StatusBadRequest
+	}
+
+	cat, err := cs.DbCat.SelectByID(catID)
 	if errors.Is(err, sql.ErrNoRows) {
-		return errors.New("there is no user with that ID"), http.StatusBadRequest
+		return nil, errors.New("there is no user with that ID"), http.StatusBadRequest
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if cat.IsArchived() {
+		return nil, errors.New("the cat is already archived"), http.StatusConflict
+	}
+	if deletion.Mode == models.CatDeletionReassign {
+		if err, respStatus := cs.checkReplacementEligibility(catID, &deletion); err != nil {
+			return nil, err, respStatus
+		}
+	}
+
+	missionIDs, err := cs.DbCat.Delete(catID, deletion)
+	switch {
+	case errors.Is(err, database.ErrCatHasActiveMissions):
+		return nil, &ActiveMissionsError{MissionIDs: missionIDs}, http.StatusConflict
+	case errors.Is(err, database.ErrReplacementCatUnavailable):
+		return nil, err, http.StatusConflict
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, errors.New("the cat is already archived"), http.StatusConflict
+	case err != nil:
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return missionIDs, nil, http.StatusOK
+}
+
+// checkReplacementEligibility checks the replacement against the requirements of the missions it takes over,
+// like AssignCatToMission does, and fills deletion.Override when the dispatcher overrides a failed check
+func (cs *CatService) checkReplacementEligibility(catID int, deletion *models.CatDeletion) (error, int) {
+	if deletion.OverrideEligibility && strings.TrimSpace(deletion.OverrideReason) == "" {
+		return errors.New("an override_reason is required to override the mission requirements"), http.StatusBadRequest
+	}
+
+	missions, err := cs.DbCat.SelectActiveMissions(catID)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	replacement, err := cs.DbCat.SelectByID(deletion.ReplacementCatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ErrReplacementCatUnavailable, http.StatusConflict
 	} else if err != nil {
 		return err, http.StatusInternalServerError
 	}
 
+	var unmet []string
+	for _, mission := range missions {
+		unmet = append(unmet, checkEligibility(mission.Requirements, replacement)...)
+	}
+	if len(unmet) == 0 {
+		return nil, http.StatusOK
+	}
+	if !deletion.OverrideEligibility {
+		return &IneligibleCatError{CatID: replacement.ID, Unmet: unmet}, http.StatusUnprocessableEntity
+	}
+
+	deletion.Override = &models.EligibilityOverride{
+		CatID:             replacement.ID,
+		Reason:            strings.TrimSpace(deletion.OverrideReason),
+		UnmetRequirements: unmet,
+	}
 	return nil, http.StatusOK
 }
 
+func (cs *CatService) RestoreCat(catID int) (*models.Cat, error, int) {
+	cat, err := cs.DbCat.SelectByID(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if !cat.IsArchived() {
+		return nil, errors.New("the cat is not archived"), http.StatusConflict
+	}
+
+	if err := cs.DbCat.Restore(catID); errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("the cat is not archived"), http.StatusConflict
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	cat, err = cs.DbCat.SelectByID(catID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return cat, nil, http.StatusOK
+}
+
+// GetCatAvailability reports whether the cat can take a mission, archived cats never can
+func (cs *CatService) GetCatAvailability(catID int) (*models.CatAvailability, error, int) {
+	cat, err := cs.DbCat.SelectByID(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	availability, err := cs.DbCat.SelectAvailability(catID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if cat.IsArchived() {
+		availability.Available = false
+		availability.Archived = true
+		availability.Since = cat.DeletedAt
+	}
+
+	return availability, nil, http.StatusOK
+}
+
 func (cs *CatService) CatValidation(cat models.Cat) error {
 	if validationErr := cs.validate.Struct(&cat); validationErr != nil {
 		return validationErr
 	}
+	if cat.Salary.IsZero() {
+		return errors.New("salary must be greater than zero")
+	}
+	if cat.HireDate > time.Now().Format(models.DateLayout) {
+		return errors.New("hire date cannot be in the future")
+	}
 	return nil
 }
 
-func fetchBreeds() ([]CatBreed, error) {
-	if time.Now().Before(cacheExpireAt) && len(cachedBreeds) > 0 {
-		return cachedBreeds, nil
-	}
-
-	req, err := http.NewRequest("GET", catAPIURL, nil)
+// matchBreed looks the breed up in the catalog and returns its canonical entry
+func (cs *CatService) matchBreed(breed string) (*CatBreed, error) {
+	breeds, err := cs.loadBreeds()
 	if err != nil {
 		return nil, err
 	}
+	return findBreed(breeds, breed)
+}
+
+// loadBreeds returns the breed catalog. A catalog served by the offline fallback of a chain cannot verify
+// breeds, so it is returned together with ErrBreedSourceUnavailable
+func (cs *CatService) loadBreeds() ([]CatBreed, error) {
+	if sourced, ok := cs.breeds.(sourcedBreedProvider); ok {
+		breeds, offline, err := sourced.SourcedBreeds()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBreedSourceUnavailable, err)
+		}
+		if offline {
+			return breeds, fmt.Errorf("%w: only the offline catalog answered", ErrBreedSourceUnavailable)
+		}
+		return breeds, nil
+	}
 
-	resp, err := httpClient.Do(req)
+	breeds, err := cs.breeds.Breeds()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrBreedSourceUnavailable, err)
 	}
-	defer resp.Body.Close()
+	return breeds, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("failed to fetch cat breeds")
+func findBreed(breeds []CatBreed, breed string) (*CatBreed, error) {
+	normalizedUserBreed := normalizeBreed(breed)
+	for _, apiBreed := range breeds {
+		if normalizeBreed(apiBreed.Name) == normalizedUserBreed {
+			return &apiBreed, nil
+		}
 	}
 
-	var breeds []CatBreed
-	if err := json.NewDecoder(resp.Body).Decode(&breeds); err != nil {
-		return nil, err
+	return nil, &InvalidBreedError{Breed: breed, Suggestions: suggestBreeds(breeds, breed)}
+}
+
+// BackfillBreeds stores canonical breed data on cats created before it was tracked,
+// cats whose breed is not in the catalog are returned untouched
+func (cs *CatService) BackfillBreeds() (int, []models.Cat, error) {
+	cats, err := cs.DbCat.SelectWithoutBreedDetails()
+	if err != nil {
+		return 0, nil, err
 	}
 
-	cachedBreeds = breeds
-	cacheExpireAt = time.Now().Add(24 * time.Hour)
+	var updated int
+	var unmatched []models.Cat
+	for _, cat := range cats {
+		breed, err := cs.matchBreed(cat.Breed)
+		if errors.Is(err, ErrBreedSourceUnavailable) {
+			return updated, unmatched, err
+		} else if err != nil {
+			unmatched = append(unmatched, cat)
+			continue
+		}
 
-	return breeds, nil
+		if err := cs.DbCat.UpdateBreed(cat.ID, *breed.Details()); err != nil {
+			return updated, unmatched, err
+		}
+		updated++
+	}
+
+	return updated, unmatched, nil
 }
 
-func isValidBreed(breed string) bool {
-	breeds, err := fetchBreeds()
+// VerifyPendingBreeds re-checks cats accepted while the breed source was down,
+// it stops early without changing anything if the source is still unreachable
+func (cs *CatService) VerifyPendingBreeds() (int, int, error) {
+	cats, err := cs.DbCat.SelectByBreedVerification(models.BreedVerificationPending)
 	if err != nil {
-		// Log the error and return false, or handle it as appropriate for your application
-		return false
+		return 0, 0, err
 	}
 
-	normalizedUserBreed := strings.ToLower(strings.TrimSpace(breed))
-	for _, apiBreed := range breeds {
-		if strings.ToLower(apiBreed.Name) == normalizedUserBreed {
-			return true
+	var verified, rejected int
+	for _, cat := range cats {
+		breed, err := cs.matchBreed(cat.Breed)
+		switch {
+		case errors.Is(err, ErrBreedSourceUnavailable):
+			return verified, rejected, err
+		case err != nil:
+			if err := cs.DbCat.SetBreedVerification(cat.ID, models.BreedVerificationRejected); err != nil {
+				return verified, rejected, err
+			}
+			rejected++
+		default:
+			if err := cs.DbCat.UpdateBreed(cat.ID, *breed.Details()); err != nil {
+				return verified, rejected, err
+			}
+			verified++
 		}
 	}
 
-	return false
+	return verified, rejected, nil
+}
+
+const (
+	minCatSearchLength  = 2
+	defaultSearchLimit  = 20
+	maxCatSearchResults = 100
+)
+
+// SearchCats fuzzy matches the query against the names and breeds of active cats
+func (cs *CatService) SearchCats(query string, limit int) ([]models.CatMatch, error, int) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minCatSearchLength {
+		return nil, fmt.Errorf("q must be at least %d characters long", minCatSearchLength), http.StatusBadRequest
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxCatSearchResults {
+		limit = maxCatSearchResults
+	}
+
+	matches, err := cs.DbCat.Search(query, limit)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if matches == nil {
+		matches = []models.CatMatch{}
+	}
+
+	return matches, nil, http.StatusOK
 }
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+	"strings"
+	"time"
+)
+
+// UnassignCat takes the cat off an active mission, which goes back to the unassigned queue
+func (ms *MissionService) UnassignCat(missionID int, reason string) (error, int) {
+	mission, err := ms.DbMission.GetMission(missionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("mission not found"), http.StatusNotFound
+	} else if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if mission.CatID == 0 {
+		return errors.New("the mission has no cat to unassign"), http.StatusConflict
+	}
+	if !canTransition(assignmentTransitions, mission.Status, models.MissionStatusUnassigned) {
+		return fmt.Errorf("the mission is %s, its cat can no longer be unassigned", mission.Status), http.StatusConflict
+	}
+
+	err = ms.DbMission.UnassignCat(missionID, strings.TrimSpace(reason))
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("the mission changed in the meantime, reload it and try again"), http.StatusConflict
+	} else if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// HandoffMission hands an active mission over to another available cat, keeping its progress. The briefing is
+// added to every open target with who handed over to whom, and the previous cat is released
+func (ms *MissionService) HandoffMission(missionID int, handoff models.MissionHandoff) (*models.Mission, error, int) {
+	handoff.Reason, handoff.Briefing = strings.TrimSpace(handoff.Reason), strings.TrimSpace(handoff.Briefing)
+	if err := ms.validate.Struct(&handoff); err != nil {
+		return nil, errors.New("CatID, Reason and Briefing are required"), http.StatusBadRequest
+	}
+
+	mission, err := ms.DbMission.GetMission(missionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("mission not found"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if mission.CatID == 0 || mission.Status.AwaitsCat() || mission.Status.IsClosed() {
+		return nil, fmt.Errorf("only active missions can be handed off, this one is %s", mission.Status), http.StatusConflict
+	}
+	if mission.CatID == handoff.CatID {
+		return nil, fmt.Errorf("cat %d is already on this mission", handoff.CatID), http.StatusConflict
+	}
+
+	if err, respStatus := ms.checkCatAssignable(handoff.CatID); err != nil {
+		return nil, err, respStatus
+	}
+	override, err, respStatus := ms.resolveEligibility(mission.Requirements, handoff.CatID, handoff.AssignmentOptions)
+	if err != nil {
+		return nil, err, respStatus
+	}
+
+	briefing := fmt.Sprintf("Handoff from cat %d to cat %d on %s: %s",
+		mission.CatID, handoff.CatID, time.Now().Format(models.DateLayout), handoff.Briefing)
+	err = ms.DbMission.HandoffMission(missionID, mission.CatID, handoff.CatID, override, handoff.Reason, briefing)
+	if err, respStatus := assignmentResult(err); err != nil {
+		return nil, err, respStatus
+	}
+
+	return ms.reloadMission(missionID)
+}
+
+// GetAssignments returns every cat the mission had, oldest first
+func (ms *MissionService) GetAssignments(missionID int) ([]models.MissionAssignment, error, int) {
+	if _, err := ms.DbMission.GetMission(missionID); errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("mission not found"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	assignments, err := ms.DbMission.SelectAssignments(missionID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	return assignments, nil, http.StatusOK
+}
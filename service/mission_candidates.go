@@ -0,0 +1,151 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"spyCat/database/models"
+	"time"
+)
+
+// recentMissionWindow is how far back assignments count towards a cat's current workload
+const recentMissionWindow = 30 * 24 * time.Hour
+
+// candidateWeights sum to 1 so the score ranges from 0 to 100
+var candidateWeights = []struct {
+	factor string
+	weight float64
+}{
+	{"experience", 0.30},
+	{"completed_missions", 0.20},
+	{"country_success", 0.20},
+	{"workload", 0.15},
+	{"salary_cost", 0.15},
+}
+
+// GetCandidates ranks the free cats for the mission, eligible cats first and then by score
+func (ms *MissionService) GetCandidates(missionID int) ([]models.MissionCandidate, error, int) {
+	mission, err := ms.DbMission.GetMission(missionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("mission not found"), http.StatusNotFound
+	}
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	stats, err := ms.DbMission.SelectCandidates(missionID, time.Now().Add(-recentMissionWindow))
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	candidates := scoreCandidates(stats, mission.Requirements)
+	return candidates, nil, http.StatusOK
+}
+
+// AutoAssign assigns the best eligible candidate through the same checks as AssignCatToMission
+func (ms *MissionService) AutoAssign(missionID int) (*models.MissionCandidate, error, int) {
+	candidates, err, respStatus := ms.GetCandidates(missionID)
+	if err != nil {
+		return nil, err, respStatus
+	}
+	if len(candidates) == 0 || !candidates[0].Eligible {
+		return nil, errors.New("no available cat meets the mission requirements"), http.StatusConflict
+	}
+
+	best := candidates[0]
+	if err, respStatus := ms.AssignCatToMission(missionID, best.CatID, models.AssignmentOptions{}); err != nil {
+		return nil, err, respStatus
+	}
+
+	return &best, nil, http.StatusOK
+}
+
+// scoreCandidates normalises every factor to 0..1 against the other candidates, more experience,
+// completed missions and successes in the target countries score higher, recent workload and a
+// higher salary score lower. Salaries are only compared within the same currency
+func scoreCandidates(stats []models.CandidateStats, req *models.MissionRequirements) []models.MissionCandidate {
+	var maxExperience, maxCompleted, maxCountry int
+	minSalary := map[string]int64{}
+	for _, s := range stats {
+		maxExperience = maxInt(maxExperience, s.Cat.YearsOfExperience)
+		maxCompleted = maxInt(maxCompleted, s.CompletedMissions)
+		maxCountry = maxInt(maxCountry, s.CountrySuccesses)
+		if cents, ok := minSalary[s.Cat.Salary.Currency]; !ok || s.Cat.Salary.Cents < cents {
+			minSalary[s.Cat.Salary.Currency] = s.Cat.Salary.Cents
+		}
+	}
+
+	candidates := make([]models.MissionCandidate, 0, len(stats))
+	for _, s := range stats {
+		salaryScore := 1.0
+		if s.Cat.Salary.Cents > 0 {
+			salaryScore = float64(minSalary[s.Cat.Salary.Currency]) / float64(s.Cat.Salary.Cents)
+		}
+
+		values := map[string][2]float64{
+			"experience":         {float64(s.Cat.YearsOfExperience), ratio(s.Cat.YearsOfExperience, maxExperience)},
+			"completed_missions": {float64(s.CompletedMissions), ratio(s.CompletedMissions, maxCompleted)},
+			"country_success":    {float64(s.CountrySuccesses), ratio(s.CountrySuccesses, maxCountry)},
+			"workload":           {float64(s.RecentMissions), 1 / float64(1+s.RecentMissions)},
+			"salary_cost":        {float64(s.Cat.Salary.Cents) / 100, salaryScore},
+		}
+
+		unmet := checkEligibility(req, &s.Cat)
+		candidate := models.MissionCandidate{
+			CatID:             s.Cat.ID,
+			Name:              s.Cat.Name,
+			Breed:             s.Cat.Breed,
+			YearsOfExperience: s.Cat.YearsOfExperience,
+			Salary:            s.Cat.Salary,
+			Eligible:          len(unmet) == 0,
+			UnmetRequirements: unmet,
+		}
+		for _, w := range candidateWeights {
+			value := values[w.factor]
+			factor := models.CandidateFactor{
+				Factor: w.factor,
+				Value:  value[0],
+				Score:  round2(value[1]),
+				Weight: w.weight,
+				Points: round2(value[1] * w.weight * 100),
+			}
+			candidate.Breakdown = append(candidate.Breakdown, factor)
+			candidate.Score += factor.Points
+		}
+		candidate.Score = round2(candidate.Score)
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Eligible != candidates[j].Eligible {
+			return candidates[i].Eligible
+		}
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].CatID < candidates[j].CatID
+	})
+
+	return candidates
+}
+
+func ratio(value, max int) float64 {
+	if max == 0 {
+		return 0
+	}
+	return float64(value) / float64(max)
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
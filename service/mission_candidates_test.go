@@ -0,0 +1,145 @@
+package service
+
+import (
+	"spyCat/database/models"
+	"strings"
+	"testing"
+)
+
+func TestCheckEligibility(t *testing.T) {
+	three := 3
+	limit := usd(300000)
+	eurLimit := models.Money{Cents: 300000, Currency: "EUR"}
+	cat := models.Cat{YearsOfExperience: 2, Breed: "Siamese", Salary: usd(250000)}
+
+	tests := []struct {
+		name      string
+		req       *models.MissionRequirements
+		wantUnmet []string
+	}{
+		{name: "no requirements"},
+		{name: "empty requirements", req: &models.MissionRequirements{}},
+		{name: "too little experience", req: &models.MissionRequirements{MinExperience: &three}, wantUnmet: []string{"min experience"}},
+		{name: "breed allowed ignoring case", req: &models.MissionRequirements{AllowedBreeds: []string{"persian", "siamese"}}},
+		{name: "breed not allowed", req: &models.MissionRequirements{AllowedBreeds: []string{"Persian"}}, wantUnmet: []string{"allowed breeds"}},
+		{name: "salary under the limit", req: &models.MissionRequirements{MaxSalary: &limit}},
+		{name: "limit in another currency", req: &models.MissionRequirements{MaxSalary: &eurLimit}, wantUnmet: []string{"limit is in EUR"}},
+		{
+			name:      "every check failing",
+			req:       &models.MissionRequirements{MinExperience: &three, AllowedBreeds: []string{"Persian"}, MaxSalary: usdPtr(100000)},
+			wantUnmet: []string{"min experience", "allowed breeds", "max salary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmet := checkEligibility(tt.req, &cat)
+			if len(unmet) != len(tt.wantUnmet) {
+				t.Fatalf("unmet = %v, want %v", unmet, tt.wantUnmet)
+			}
+			for i, want := range tt.wantUnmet {
+				if !strings.Contains(unmet[i], want) {
+					t.Errorf("unmet[%d] = %q, want it to mention %q", i, unmet[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreCandidates(t *testing.T) {
+	candidate := func(id, experience, completed, recent int, salary models.Money, breed string) models.CandidateStats {
+		return models.CandidateStats{
+			Cat:               models.Cat{ID: id, YearsOfExperience: experience, Breed: breed, Salary: salary},
+			CompletedMissions: completed,
+			RecentMissions:    recent,
+		}
+	}
+	factor := func(c models.MissionCandidate, name string) models.CandidateFactor {
+		for _, f := range c.Breakdown {
+			if f.Factor == name {
+				return f
+			}
+		}
+		t.Fatalf("candidate %d has no %s factor", c.CatID, name)
+		return models.CandidateFactor{}
+	}
+
+	t.Run("zero maximums score zero", func(t *testing.T) {
+		candidates := scoreCandidates([]models.CandidateStats{candidate(1, 0, 0, 0, usd(100000), "Siamese")}, nil)
+		for _, name := range []string{"experience", "completed_missions", "country_success"} {
+			if f := factor(candidates[0], name); f.Score != 0 || f.Points != 0 {
+				t.Errorf("%s = %+v, want a zero score", name, f)
+			}
+		}
+		// no recent missions and the cheapest salary are worth full points
+		if candidates[0].Score != 30 {
+			t.Errorf("score = %v, want 30", candidates[0].Score)
+		}
+	})
+
+	t.Run("factors normalised against the best candidate", func(t *testing.T) {
+		candidates := scoreCandidates([]models.CandidateStats{
+			candidate(1, 10, 4, 1, usd(200000), "Siamese"),
+			candidate(2, 5, 2, 0, usd(100000), "Siamese"),
+		}, nil)
+		byID := map[int]models.MissionCandidate{}
+		for _, c := range candidates {
+			byID[c.CatID] = c
+		}
+		tests := []struct {
+			catID  int
+			factor string
+			want   float64
+		}{
+			{1, "experience", 1},
+			{2, "experience", 0.5},
+			{1, "completed_missions", 1},
+			{2, "completed_missions", 0.5},
+			{1, "workload", 0.5},
+			{2, "workload", 1},
+			{1, "salary_cost", 0.5},
+			{2, "salary_cost", 1},
+		}
+		for _, tt := range tests {
+			if got := factor(byID[tt.catID], tt.factor).Score; got != tt.want {
+				t.Errorf("cat %d %s = %v, want %v", tt.catID, tt.factor, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("salaries compared within their currency", func(t *testing.T) {
+		candidates := scoreCandidates([]models.CandidateStats{
+			candidate(1, 1, 0, 0, usd(200000), "Siamese"),
+			candidate(2, 1, 0, 0, models.Money{Cents: 900000, Currency: "EUR"}, "Siamese"),
+		}, nil)
+		for _, c := range candidates {
+			if f := factor(c, "salary_cost"); f.Score != 1 {
+				t.Errorf("cat %d salary_cost = %v, want 1 as the cheapest in its currency", c.CatID, f.Score)
+			}
+		}
+	})
+
+	t.Run("eligible before ineligible, then by score and ID", func(t *testing.T) {
+		req := &models.MissionRequirements{AllowedBreeds: []string{"Siamese"}}
+		candidates := scoreCandidates([]models.CandidateStats{
+			candidate(1, 10, 10, 0, usd(100000), "Persian"),
+			candidate(2, 1, 0, 3, usd(300000), "Siamese"),
+			candidate(3, 5, 5, 0, usd(100000), "Siamese"),
+			candidate(4, 5, 5, 0, usd(100000), "Siamese"),
+		}, req)
+
+		var order []int
+		for _, c := range candidates {
+			order = append(order, c.CatID)
+		}
+		want := []int{3, 4, 2, 1}
+		for i := range want {
+			if order[i] != want[i] {
+				t.Fatalf("order = %v, want %v", order, want)
+			}
+		}
+		if candidates[3].Eligible || len(candidates[3].UnmetRequirements) != 1 {
+			t.Errorf("cat 1 = %+v, want it ineligible for its breed", candidates[3])
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+	"strings"
+)
+
+// IneligibleCatError is returned when a cat fails the mission requirements, Unmet lists each failed one
+type IneligibleCatError struct {
+	CatID int
+	Unmet []string
+}
+
+func (e *IneligibleCatError) Error() string {
+	return fmt.Sprintf("cat %d does not meet the mission requirements, pass OverrideEligibility with an OverrideReason to assign it anyway", e.CatID)
+}
+
+// checkEligibility returns a description of every requirement the cat does not meet
+func checkEligibility(req *models.MissionRequirements, cat *models.Cat) []string {
+	if req.IsEmpty() {
+		return nil
+	}
+
+	var unmet []string
+	if req.MinExperience != nil && cat.YearsOfExperience < *req.MinExperience {
+		unmet = append(unmet, fmt.Sprintf("min experience: requires %d years, cat has %d", *req.MinExperience, cat.YearsOfExperience))
+	}
+
+	if len(req.AllowedBreeds) > 0 {
+		allowed := false
+		for _, breed := range req.AllowedBreeds {
+			if strings.EqualFold(breed, cat.Breed) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			unmet = append(unmet, fmt.Sprintf("allowed breeds: %s is not one of %s", cat.Breed, strings.Join(req.AllowedBreeds, ", ")))
+		}
+	}
+
+	if req.MaxSalary != nil {
+		switch {
+		case req.MaxSalary.Currency != cat.Salary.Currency:
+			unmet = append(unmet, fmt.Sprintf("max salary: limit is in %s, cat is paid in %s", req.MaxSalary.Currency, cat.Salary.Currency))
+		case cat.Salary.Cents > req.MaxSalary.Cents:
+			unmet = append(unmet, fmt.Sprintf("max salary: limit is %s, cat earns %s", req.MaxSalary, cat.Salary))
+		}
+	}
+
+	return unmet
+}
+
+// resolveEligibility checks the cat against the requirements. When the dispatcher overrides a failed
+// check it returns the override to record on the mission, otherwise the override is nil
+func (ms *MissionService) resolveEligibility(req *models.MissionRequirements, catID int, opts models.AssignmentOptions) (*models.EligibilityOverride, error, int) {
+	if opts.OverrideEligibility && strings.TrimSpace(opts.OverrideReason) == "" {
+		return nil, errors.New("an OverrideReason is required to override the mission requirements"), http.StatusBadRequest
+	}
+	if req.IsEmpty() {
+		return nil, nil, http.StatusOK
+	}
+
+	cat, err := ms.DbMission.GetCat(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("the specified cat does not exist"), http.StatusBadRequest
+	}
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	unmet := checkEligibility(req, cat)
+	if len(unmet) == 0 {
+		return nil, nil, http.StatusOK
+	}
+	if !opts.OverrideEligibility {
+		return nil, &IneligibleCatError{CatID: catID, Unmet: unmet}, http.StatusUnprocessableEntity
+	}
+
+	return &models.EligibilityOverride{
+		CatID:             catID,
+		Reason:            strings.TrimSpace(opts.OverrideReason),
+		UnmetRequirements: unmet,
+	}, nil, http.StatusOK
+}
+
+func (ms *MissionService) validateRequirements(req *models.MissionRequirements) error {
+	if req.MinExperience != nil && *req.MinExperience < 0 {
+		return errors.New("requirements: MinExperience cannot be negative")
+	}
+	for _, breed := range req.AllowedBreeds {
+		if strings.TrimSpace(breed) == "" {
+			return errors.New("requirements: AllowedBreeds cannot contain empty names")
+		}
+	}
+	if req.MaxSalary != nil && req.MaxSalary.IsZero() {
+		return errors.New("requirements: MaxSalary must be greater than zero")
+	}
+	return nil
+}
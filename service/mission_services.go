@@ -1,22 +1,31 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/lib/pq"
 	"net/http"
 	"spyCat/database"
 	"spyCat/database/models"
+	"strings"
 )
 
 type MissionServiceInterface interface {
-	CreateMission(mission *models.Mission) (*models.Mission, error, int)
+	CreateMission(mission *models.Mission, opts models.AssignmentOptions) (*models.Mission, error, int)
 	DeleteMission(id int) error
 	UpdateMission(mission *models.Mission) (*models.Mission, error, int)
-	CompleteMission(id int) error
-	AssignCatToMission(missionID, catID int) (error, int)
-	ListMissions() (*[]models.Mission, error)
+	CompleteMission(id int) (error, int)
+	TransitionMission(id int, to models.MissionStatus) (*models.Mission, error, int)
+	AssignCatToMission(missionID, catID int, opts models.AssignmentOptions) (error, int)
+	UnassignCat(missionID int, reason string) (error, int)
+	HandoffMission(missionID int, handoff models.MissionHandoff) (*models.Mission, error, int)
+	GetAssignments(missionID int) ([]models.MissionAssignment, error, int)
+	ListMissions(filter models.MissionFilter) (*[]models.Mission, error)
 	GetMission(id int) (*models.Mission, error)
+	GetCandidates(missionID int) ([]models.MissionCandidate, error, int)
+	AutoAssign(missionID int) (*models.MissionCandidate, error, int)
 }
 
 type MissionService struct {
@@ -28,27 +37,66 @@ func NewMissionService(DbMission database.MissionDatabaseInterface, validate *va
 	return &MissionService{DbMission: DbMission, validate: validate}
 }
 
-func (ms *MissionService) CreateMission(mission *models.Mission) (*models.Mission, error, int) {
-	isAvailable, err := ms.DbMission.IsCatAvailable(mission.CatID)
+// checkCatAssignable rejects cats that are archived or already busy with an active mission
+func (ms *MissionService) checkCatAssignable(catID int) (error, int) {
+	isArchived, err := ms.DbMission.IsCatArchived(catID)
 	if err != nil {
-		return nil, err, http.StatusInternalServerError
+		return err, http.StatusInternalServerError
+	}
+	if isArchived {
+		return errors.New("the selected cat is archived and cannot be assigned to missions"), http.StatusConflict
+	}
+
+	isAvailable, err := ms.DbMission.IsCatAvailable(catID)
+	if err != nil {
+		return err, http.StatusInternalServerError
 	}
 	if !isAvailable {
-		return nil, errors.New("the selected cat is already assigned to an active mission"), http.StatusConflict
+		return errors.New("the selected cat is already assigned to an active mission"), http.StatusConflict
+	}
+
+	return nil, http.StatusOK
+}
+
+// CreateMission creates an assigned mission when a cat is given. Without one the mission joins the unassigned
+// queue, or stays a draft when created with the draft status
+func (ms *MissionService) CreateMission(mission *models.Mission, opts models.AssignmentOptions) (*models.Mission, error, int) {
+	if mission.CatID != 0 {
+		if err, respStatus := ms.checkCatAssignable(mission.CatID); err != nil {
+			return nil, err, respStatus
+		}
+	} else if mission.Status != "" && !mission.Status.AwaitsCat() {
+		return nil, errors.New("a mission without a cat can only be created as draft or unassigned"), http.StatusBadRequest
 	}
 
 	if len(mission.Targets) < 1 || len(mission.Targets) > 3 {
 		return nil, errors.New("a mission must have between 1 and 3 targets"), http.StatusBadRequest
 	}
 
-	mission.Status = "in_progress"
+	if mission.Requirements != nil {
+		if err := ms.validateRequirements(mission.Requirements); err != nil {
+			return nil, err, http.StatusBadRequest
+		}
+	}
+
+	switch {
+	case mission.CatID != 0:
+		override, err, respStatus := ms.resolveEligibility(mission.Requirements, mission.CatID, opts)
+		if err != nil {
+			return nil, err, respStatus
+		}
+		mission.EligibilityOverride = override
+		mission.Status = models.MissionStatusAssigned
+	case mission.Status != models.MissionStatusDraft:
+		mission.Status = models.MissionStatusUnassigned
+	}
+
 	for i := range mission.Targets {
-		mission.Targets[i].Status = "in_progress"
+		mission.Targets[i].Status = models.TargetStatusInProgress
 	}
 
-	err = ms.DbMission.CreateMission(mission)
-	if err != nil {
-		return nil, err, http.StatusInternalServerError
+	if err, respStatus := assignmentResult(ms.DbMission.CreateMission(mission)); err != nil {
+		return nil, err, respStatus
 	}
 
 	return mission, nil, http.StatusCreated
@@ -67,7 +115,31 @@ func (ms *MissionService) DeleteMission(id int) error {
 }
 
 func (ms *MissionService) UpdateMission(mission *models.Mission) (*models.Mission, error, int) {
-	if mission.CatID != 0 {
+	current, err := ms.DbMission.GetMission(mission.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("mission not found"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if mission.Status != "" && mission.Status != current.Status {
+		return nil, fmt.Errorf("the status cannot be edited, use POST /missions/%d/transitions", mission.ID), http.StatusBadRequest
+	}
+	if current.Status.IsClosed() {
+		return nil, fmt.Errorf("the mission is %s and can no longer be edited", current.Status), http.StatusConflict
+	}
+
+	if mission.CatID == 0 {
+		return nil, fmt.Errorf("CatID is required, take the cat off with DELETE /missions/%d/assignment", mission.ID), http.StatusBadRequest
+	}
+	if current.CatID != 0 && mission.CatID != current.CatID {
+		return nil, fmt.Errorf("this mission is already assigned to cat %d, replace it with PUT /missions/%d/assign and a ReassignReason", current.CatID, mission.ID), http.StatusConflict
+	}
+
+	if mission.CatID != current.CatID {
+		if !canTransition(assignmentTransitions, current.Status, models.MissionStatusAssigned) {
+			return nil, fmt.Errorf("a %s mission cannot be assigned", current.Status), http.StatusConflict
+		}
+
 		catExists, err := ms.DbMission.DoesCatExist(mission.CatID)
 		if err != nil {
 			return nil, err, http.StatusInternalServerError
@@ -76,16 +148,17 @@ func (ms *MissionService) UpdateMission(mission *models.Mission) (*models.Missio
 			return nil, errors.New("the specified cat does not exist"), http.StatusBadRequest
 		}
 
-		isAvailable, err := ms.DbMission.IsCatAvailable(mission.CatID)
-		if err != nil {
-			return nil, err, http.StatusBadRequest
+		if err, respStatus := ms.checkCatAssignable(mission.CatID); err != nil {
+			return nil, err, respStatus
 		}
-		if !isAvailable {
-			return nil, errors.New("the selected cat is already assigned to an active mission"), http.StatusConflict
+
+		// there is no way to record an override here, overrides go through the assign endpoint
+		if _, err, respStatus := ms.resolveEligibility(current.Requirements, mission.CatID, models.AssignmentOptions{}); err != nil {
+			return nil, err, respStatus
 		}
 	}
 
-	err := ms.DbMission.UpdateMission(mission)
+	err = ms.DbMission.UpdateMission(mission)
 	if err != nil {
 		// Check for foreign key violation
 		var pqErr *pq.Error
@@ -96,29 +169,21 @@ func (ms *MissionService) UpdateMission(mission *models.Mission) (*models.Missio
 				return nil, errors.New("invalid cat ID: the specified cat does not exist"), http.StatusBadRequest
 			}
 		}
-		return nil, err, http.StatusInternalServerError
+		err, respStatus := assignmentResult(err)
+		return nil, err, respStatus
 	}
 
-	return mission, nil, http.StatusOK
+	return ms.reloadMission(mission.ID)
 }
 
-func (ms *MissionService) CompleteMission(id int) error {
-	mission, err := ms.DbMission.GetMission(id)
-	if err != nil {
-		return err
-	}
-
-	for _, target := range mission.Targets {
-		if target.Status != "completed" {
-			return errors.New("all targets must be completed before completing the mission")
-		}
-	}
-
-	return ms.DbMission.CompleteMission(id)
+// CompleteMission is a shortcut for the transition to completed
+func (ms *MissionService) CompleteMission(id int) (error, int) {
+	_, err, respStatus := ms.TransitionMission(id, models.MissionStatusCompleted)
+	return err, respStatus
 }
 
-func (ms *MissionService) ListMissions() (*[]models.Mission, error) {
-	mission, err := ms.DbMission.ListMissions()
+func (ms *MissionService) ListMissions(filter models.MissionFilter) (*[]models.Mission, error) {
+	mission, err := ms.DbMission.ListMissions(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -130,23 +195,59 @@ func (ms *MissionService) GetMission(id int) (*models.Mission, error) {
 	return ms.DbMission.GetMission(id)
 }
 
-func (ms *MissionService) AssignCatToMission(missionID, catID int) (error, int) {
-	// Check if the cat is available
-	isAvailable, err := ms.DbMission.IsCatAvailable(catID)
+// AssignCatToMission puts the cat on a mission waiting for one. A mission that already has a cat
+// gets the new one only when opts.ReassignReason says why the current cat is replaced
+func (ms *MissionService) AssignCatToMission(missionID, catID int, opts models.AssignmentOptions) (error, int) {
+	mission, err := ms.DbMission.GetMission(missionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("mission not found"), http.StatusNotFound
+	}
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
-	if !isAvailable {
-		return errors.New("the selected cat is already assigned to an active mission"), http.StatusConflict
+	if mission.Status.IsClosed() {
+		return fmt.Errorf("the mission is %s and can no longer be assigned", mission.Status), http.StatusConflict
 	}
 
-	isMissionAssigned, err := ms.DbMission.IsMissionAssigned(missionID)
-	if err != nil {
-		return err, http.StatusInternalServerError
+	if mission.CatID == 0 && !canTransition(assignmentTransitions, mission.Status, models.MissionStatusAssigned) {
+		return fmt.Errorf("a %s mission cannot be assigned", mission.Status), http.StatusConflict
 	}
-	if isMissionAssigned {
-		return errors.New("this mission is already assigned to a cat"), http.StatusBadRequest
+	if mission.CatID != 0 {
+		if mission.CatID == catID {
+			return fmt.Errorf("cat %d is already assigned to this mission", catID), http.StatusConflict
+		}
+		if strings.TrimSpace(opts.ReassignReason) == "" {
+			return fmt.Errorf("this mission is already assigned to cat %d, pass a ReassignReason to replace it", mission.CatID), http.StatusConflict
+		}
 	}
 
-	return ms.DbMission.AssignCatToMission(missionID, catID), http.StatusOK
+	// Check if the cat can take the mission
+	if err, respStatus := ms.checkCatAssignable(catID); err != nil {
+		return err, respStatus
+	}
+
+	override, err, respStatus := ms.resolveEligibility(mission.Requirements, catID, opts)
+	if err != nil {
+		return err, respStatus
+	}
+
+	return assignmentResult(ms.DbMission.AssignCatToMission(missionID, mission.CatID, catID, override, strings.TrimSpace(opts.ReassignReason)))
+}
+
+// assignmentResult maps the outcome of a write putting a cat on a mission. The availability checks done before it
+// give friendlier errors, but only the locks taken by the write are authoritative, so losing a race to another
+// assignment is reported as 409 like the checks would have
+func assignmentResult(err error) (error, int) {
+	var pqErr *pq.Error
+	switch {
+	case err == nil:
+		return nil, http.StatusOK
+	case errors.Is(err, database.ErrCatUnavailable):
+		return err, http.StatusConflict
+	case errors.Is(err, sql.ErrNoRows):
+		return errors.New("the mission changed in the meantime, reload it and try again"), http.StatusConflict
+	case errors.As(err, &pqErr) && pqErr.Code == "40P01": // deadlock_detected
+		return errors.New("the mission or the cat is being changed by another request, try again"), http.StatusConflict
+	}
+	return err, http.StatusInternalServerError
 }
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"spyCat/database"
+	"spyCat/database/models"
+	"testing"
+)
+
+// updateMissionDatabase serves one mission and fails the test if the update reaches the database
+type updateMissionDatabase struct {
+	database.MissionDatabaseInterface
+	t       *testing.T
+	current models.Mission
+}
+
+func (db updateMissionDatabase) GetMission(id int) (*models.Mission, error) {
+	mission := db.current
+	return &mission, nil
+}
+
+func (db updateMissionDatabase) UpdateMission(mission *models.Mission) error {
+	db.t.Fatalf("mission %d should not have been written", mission.ID)
+	return nil
+}
+
+func TestUpdateMissionDoesNotReplaceOrRemoveTheCat(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    models.Mission
+		catID      int
+		wantStatus int
+	}{
+		{"replace the cat", models.Mission{ID: 1, CatID: 3, Status: models.MissionStatusInProgress}, 4, http.StatusConflict},
+		{"remove the cat", models.Mission{ID: 1, CatID: 3, Status: models.MissionStatusAssigned}, 0, http.StatusBadRequest},
+		{"no cat given for a queued mission", models.Mission{ID: 1, Status: models.MissionStatusUnassigned}, 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewMissionService(updateMissionDatabase{t: t, current: tt.current}, validator.New())
+			_, err, status := ms.UpdateMission(&models.Mission{ID: tt.current.ID, CatID: tt.catID})
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+)
+
+// missionTransitions lists the states each mission state can move to through POST /missions/:id/transitions,
+// completed and aborted are final
+var missionTransitions = map[models.MissionStatus][]models.MissionStatus{
+	models.MissionStatusDraft:      {models.MissionStatusUnassigned, models.MissionStatusAborted},
+	models.MissionStatusUnassigned: {models.MissionStatusDraft, models.MissionStatusAborted},
+	models.MissionStatusAssigned:   {models.MissionStatusInProgress, models.MissionStatusOnHold, models.MissionStatusAborted},
+	models.MissionStatusInProgress: {models.MissionStatusOnHold, models.MissionStatusCompleted, models.MissionStatusAborted},
+	models.MissionStatusOnHold:     {models.MissionStatusInProgress, models.MissionStatusAborted},
+	models.MissionStatusCompleted:  {},
+	models.MissionStatusAborted:    {},
+}
+
+// assignmentTransitions are the state changes made by putting a cat on a mission or taking it off, so
+// they are not advertised as allowed transitions. They are the only changes outside missionTransitions:
+// the assign and unassign endpoints check them and archiving a cat with ?mode=unassign makes the
+// active -> unassigned ones for all of its missions
+var assignmentTransitions = map[models.MissionStatus][]models.MissionStatus{
+	models.MissionStatusDraft:      {models.MissionStatusAssigned},
+	models.MissionStatusUnassigned: {models.MissionStatusAssigned},
+	models.MissionStatusAssigned:   {models.MissionStatusUnassigned},
+	models.MissionStatusInProgress: {models.MissionStatusUnassigned},
+	models.MissionStatusOnHold:     {models.MissionStatusUnassigned},
+}
+
+// InvalidTransitionError is returned when a mission cannot move to the requested state, Allowed lists where it can go
+type InvalidTransitionError struct {
+	From    models.MissionStatus
+	To      models.MissionStatus
+	Allowed []models.MissionStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("a mission cannot go from %s to %s", e.From, e.To)
+}
+
+func canTransition(transitions map[models.MissionStatus][]models.MissionStatus, from, to models.MissionStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionMission moves the mission to the requested state when the state machine allows it
+func (ms *MissionService) TransitionMission(id int, to models.MissionStatus) (*models.Mission, error, int) {
+	if _, ok := missionTransitions[to]; !ok {
+		return nil, fmt.Errorf("unknown mission status %q", to), http.StatusBadRequest
+	}
+
+	mission, err := ms.DbMission.GetMission(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("mission not found"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	if canTransition(assignmentTransitions, mission.Status, to) {
+		if to == models.MissionStatusUnassigned {
+			return nil, fmt.Errorf("take the cat off with DELETE /missions/%d/assignment to move the mission to unassigned", id), http.StatusConflict
+		}
+		return nil, fmt.Errorf("assign a cat with PUT /missions/%d/assign to move the mission to %s", id, to), http.StatusConflict
+	}
+	if !canTransition(missionTransitions, mission.Status, to) {
+		return nil, &InvalidTransitionError{From: mission.Status, To: to, Allowed: missionTransitions[mission.Status]}, http.StatusConflict
+	}
+
+	switch to {
+	case models.MissionStatusCompleted:
+		for _, target := range mission.Targets {
+			if target.Status != models.TargetStatusCompleted {
+				return nil, errors.New("all targets must be completed before completing the mission"), http.StatusConflict
+			}
+		}
+	}
+
+	err = ms.DbMission.TransitionMission(id, mission.Status, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("the mission changed in the meantime, reload it and try again"), http.StatusConflict
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return ms.reloadMission(id)
+}
+
+func (ms *MissionService) reloadMission(id int) (*models.Mission, error, int) {
+	mission, err := ms.DbMission.GetMission(id)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	return mission, nil, http.StatusOK
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"spyCat/database/models"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions map[models.MissionStatus][]models.MissionStatus
+		from, to    models.MissionStatus
+		want        bool
+	}{
+		{"publish a draft", missionTransitions, models.MissionStatusDraft, models.MissionStatusUnassigned, true},
+		{"back to draft", missionTransitions, models.MissionStatusUnassigned, models.MissionStatusDraft, true},
+		{"start", missionTransitions, models.MissionStatusAssigned, models.MissionStatusInProgress, true},
+		{"pause", missionTransitions, models.MissionStatusInProgress, models.MissionStatusOnHold, true},
+		{"resume", missionTransitions, models.MissionStatusOnHold, models.MissionStatusInProgress, true},
+		{"complete", missionTransitions, models.MissionStatusInProgress, models.MissionStatusCompleted, true},
+		{"abort a draft", missionTransitions, models.MissionStatusDraft, models.MissionStatusAborted, true},
+		{"abort on hold", missionTransitions, models.MissionStatusOnHold, models.MissionStatusAborted, true},
+		{"complete before starting", missionTransitions, models.MissionStatusAssigned, models.MissionStatusCompleted, false},
+		{"complete on hold", missionTransitions, models.MissionStatusOnHold, models.MissionStatusCompleted, false},
+		{"reopen completed", missionTransitions, models.MissionStatusCompleted, models.MissionStatusInProgress, false},
+		{"reopen aborted", missionTransitions, models.MissionStatusAborted, models.MissionStatusDraft, false},
+		{"assign through transitions", missionTransitions, models.MissionStatusDraft, models.MissionStatusAssigned, false},
+		{"assign queued through transitions", missionTransitions, models.MissionStatusUnassigned, models.MissionStatusAssigned, false},
+		{"same state", missionTransitions, models.MissionStatusAssigned, models.MissionStatusAssigned, false},
+		{"unknown state", missionTransitions, models.MissionStatus("lost"), models.MissionStatusAborted, false},
+		{"assign a draft", assignmentTransitions, models.MissionStatusDraft, models.MissionStatusAssigned, true},
+		{"assign queued", assignmentTransitions, models.MissionStatusUnassigned, models.MissionStatusAssigned, true},
+		{"assign completed", assignmentTransitions, models.MissionStatusCompleted, models.MissionStatusAssigned, false},
+		{"unassign assigned", assignmentTransitions, models.MissionStatusAssigned, models.MissionStatusUnassigned, true},
+		{"unassign in progress", assignmentTransitions, models.MissionStatusInProgress, models.MissionStatusUnassigned, true},
+		{"unassign on hold", assignmentTransitions, models.MissionStatusOnHold, models.MissionStatusUnassigned, true},
+		{"unassign completed", assignmentTransitions, models.MissionStatusCompleted, models.MissionStatusUnassigned, false},
+		{"unassign aborted", assignmentTransitions, models.MissionStatusAborted, models.MissionStatusUnassigned, false},
+		{"unassign a draft", assignmentTransitions, models.MissionStatusDraft, models.MissionStatusUnassigned, false},
+		{"unassign through transitions", missionTransitions, models.MissionStatusInProgress, models.MissionStatusUnassigned, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canTransition(tt.transitions, tt.from, tt.to); got != tt.want {
+				t.Errorf("canTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissionTransitionsNeverAdvertiseAssigned(t *testing.T) {
+	for from, allowed := range missionTransitions {
+		for _, to := range allowed {
+			if to == models.MissionStatusAssigned {
+				t.Errorf("%s lists assigned, which only the assign endpoints can reach", from)
+			}
+		}
+	}
+}
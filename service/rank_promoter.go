@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// RankPromoter records rank changes as cats gain experience and complete missions
+type RankPromoter struct {
+	cats     *CatService
+	interval time.Duration
+}
+
+func NewRankPromoter(cats *CatService, interval time.Duration) *RankPromoter {
+	return &RankPromoter{cats: cats, interval: interval}
+}
+
+// Run promotes right away, then re-checks on every interval until ctx is cancelled
+func (rp *RankPromoter) Run(ctx context.Context) {
+	rp.promote()
+	runEvery(ctx, rp.interval, rp.promote)
+}
+
+func (rp *RankPromoter) promote() {
+	changed, err := rp.cats.PromoteCats()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to record rank changes")
+	}
+
+	if changed > 0 {
+		log.Info().Msgf("Recorded %d rank changes", changed)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+	"strconv"
+	"strings"
+)
+
+// ParseRankLadder reads the RANKS setting: comma separated name:min_experience:min_completed_missions:salary_min-salary_max
+// entries from the lowest rank up, the salary band amounts are in the given currency
+func ParseRankLadder(spec, currency string) (models.RankLadder, error) {
+	var ladder models.RankLadder
+	seen := map[string]bool{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 4 || parts[0] == "" {
+			return nil, fmt.Errorf("rank %q: expected name:min_experience:min_completed_missions:salary_min-salary_max", entry)
+		}
+
+		rank := models.Rank{Name: parts[0]}
+		if seen[rank.Name] {
+			return nil, fmt.Errorf("rank %q is listed twice", rank.Name)
+		}
+		seen[rank.Name] = true
+
+		var err error
+		if rank.MinExperience, err = strconv.Atoi(parts[1]); err != nil || rank.MinExperience < 0 {
+			return nil, fmt.Errorf("rank %q: min_experience must be a non-negative integer", rank.Name)
+		}
+		if rank.MinCompletedMissions, err = strconv.Atoi(parts[2]); err != nil || rank.MinCompletedMissions < 0 {
+			return nil, fmt.Errorf("rank %q: min_completed_missions must be a non-negative integer", rank.Name)
+		}
+
+		minSalary, maxSalary, ok := strings.Cut(parts[3], "-")
+		if !ok {
+			return nil, fmt.Errorf("rank %q: salary band must look like 1000-3000", rank.Name)
+		}
+		if rank.SalaryBand.Min, err = models.ParseMoney(minSalary, currency); err != nil {
+			return nil, fmt.Errorf("rank %q: salary band minimum: %w", rank.Name, err)
+		}
+		if rank.SalaryBand.Max, err = models.ParseMoney(maxSalary, currency); err != nil {
+			return nil, fmt.Errorf("rank %q: salary band maximum: %w", rank.Name, err)
+		}
+		if rank.SalaryBand.Min.Cents > rank.SalaryBand.Max.Cents {
+			return nil, fmt.Errorf("rank %q: salary band minimum is above its maximum", rank.Name)
+		}
+
+		if len(ladder) == 0 && (rank.MinExperience != 0 || rank.MinCompletedMissions != 0) {
+			return nil, fmt.Errorf("rank %q: the first rank cannot have requirements", rank.Name)
+		}
+		if len(ladder) > 0 {
+			previous := ladder[len(ladder)-1]
+			if rank.MinExperience < previous.MinExperience || rank.MinCompletedMissions < previous.MinCompletedMissions {
+				return nil, fmt.Errorf("rank %q: requirements cannot be lower than those of %q", rank.Name, previous.Name)
+			}
+		}
+
+		ladder = append(ladder, rank)
+	}
+
+	return ladder, nil
+}
+
+// SalaryBandError is returned when a salary falls outside the band of the cat's rank
+type SalaryBandError struct {
+	Rank   string
+	Band   models.SalaryBand
+	Salary models.Money
+}
+
+func (e *SalaryBandError) Error() string {
+	if !e.Band.AppliesTo(e.Salary) {
+		return fmt.Sprintf("salary %s must be paid in %s, the currency of the %s band", e.Salary, e.Band.Min.Currency, e.Rank)
+	}
+	return fmt.Sprintf("salary %s is outside the %s band of %s to %s", e.Salary, e.Rank, e.Band.Min, e.Band.Max)
+}
+
+// checkSalaryBand refuses a salary outside the band of the rank the cat currently qualifies for.
+// The bands all share RANK_SALARY_CURRENCY, so a salary in another currency is refused too
+func (cs *CatService) checkSalaryBand(catID int, salary models.Money) (error, int) {
+	if len(cs.payroll.Ranks) == 0 {
+		return nil, http.StatusOK
+	}
+
+	stats, err := cs.DbCat.SelectRankStats(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return err, http.StatusInternalServerError
+	}
+
+	rank := cs.payroll.Ranks[cs.payroll.Ranks.For(stats.YearsOfExperience, stats.CompletedMissions)]
+	if !rank.SalaryBand.Contains(salary) {
+		return &SalaryBandError{Rank: rank.Name, Band: rank.SalaryBand, Salary: salary}, http.StatusUnprocessableEntity
+	}
+	return nil, http.StatusOK
+}
+
+// GetCatRank explains which rank the cat qualifies for, what the next one takes and its promotion history
+func (cs *CatService) GetCatRank(catID int) (*models.CatRank, error, int) {
+	if len(cs.payroll.Ranks) == 0 {
+		return nil, errors.New("no ranks are configured"), http.StatusNotFound
+	}
+
+	stats, err := cs.DbCat.SelectRankStats(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	promotions, err := cs.DbCat.SelectPromotions(catID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	index := cs.payroll.Ranks.For(stats.YearsOfExperience, stats.CompletedMissions)
+	rank := cs.payroll.Ranks[index]
+	catRank := &models.CatRank{
+		CatID:             catID,
+		Rank:              rank.Name,
+		RecordedRank:      stats.Rank,
+		YearsOfExperience: stats.YearsOfExperience,
+		CompletedMissions: stats.CompletedMissions,
+		SalaryBand:        rank.SalaryBand,
+		Promotions:        promotions,
+		Explanation: []string{fmt.Sprintf("%s: %d years of experience (needs %d) and %d completed missions (needs %d)",
+			rank.Name, stats.YearsOfExperience, rank.MinExperience, stats.CompletedMissions, rank.MinCompletedMissions)},
+	}
+
+	if index+1 < len(cs.payroll.Ranks) {
+		next := cs.payroll.Ranks[index+1]
+		catRank.Next = &models.RankProgress{
+			Rank:              next.Name,
+			MissingExperience: maxInt(0, next.MinExperience-stats.YearsOfExperience),
+			MissingMissions:   maxInt(0, next.MinCompletedMissions-stats.CompletedMissions),
+		}
+		catRank.Explanation = append(catRank.Explanation, fmt.Sprintf("%s needs %d more years of experience and %d more completed missions",
+			next.Name, catRank.Next.MissingExperience, catRank.Next.MissingMissions))
+	} else {
+		catRank.Explanation = append(catRank.Explanation, "this is the highest rank")
+	}
+	if stats.Rank != "" && stats.Rank != rank.Name {
+		catRank.Explanation = append(catRank.Explanation, fmt.Sprintf("the change from %s will be recorded on the next promotion run", stats.Rank))
+	}
+
+	return catRank, nil, http.StatusOK
+}
+
+// PromoteCats records the rank of every active cat whose computed rank differs from the recorded one
+func (cs *CatService) PromoteCats() (int, error) {
+	if len(cs.payroll.Ranks) == 0 {
+		return 0, nil
+	}
+
+	all, err := cs.DbCat.SelectAllRankStats()
+	if err != nil {
+		return 0, err
+	}
+
+	var changed int
+	for _, stats := range all {
+		rank := cs.payroll.Ranks[cs.payroll.Ranks.For(stats.YearsOfExperience, stats.CompletedMissions)]
+		if rank.Name == stats.Rank {
+			continue
+		}
+
+		recorded, err := cs.DbCat.RecordRankChange(stats, rank.Name)
+		if err != nil {
+			return changed, err
+		}
+		if recorded {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
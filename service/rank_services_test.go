@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"spyCat/database"
+	"spyCat/database/models"
+	"strings"
+	"testing"
+)
+
+// rankCatDatabase answers SelectRankStats with fixed stats
+type rankCatDatabase struct {
+	database.CatDatabaseInterface
+	stats models.RankStats
+}
+
+func (db rankCatDatabase) SelectRankStats(catID int) (*models.RankStats, error) {
+	stats := db.stats
+	stats.CatID = catID
+	return &stats, nil
+}
+
+func TestCheckSalaryBand(t *testing.T) {
+	ladder, err := ParseRankLadder("recruit:0:0:1000-3000,agent:3:5:3000-6000", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eur := func(cents int64) models.Money { return models.Money{Cents: cents, Currency: "EUR"} }
+
+	tests := []struct {
+		name       string
+		ranks      models.RankLadder
+		stats      models.RankStats
+		salary     models.Money
+		wantStatus int
+		wantErr    string
+	}{
+		{"inside the recruit band", ladder, models.RankStats{}, usd(200000), http.StatusOK, ""},
+		{"above the recruit band", ladder, models.RankStats{}, usd(400000), http.StatusUnprocessableEntity, "outside the recruit band"},
+		{"inside the agent band", ladder, models.RankStats{YearsOfExperience: 3, CompletedMissions: 5}, usd(400000), http.StatusOK, ""},
+		{"other currency", ladder, models.RankStats{}, eur(200000), http.StatusUnprocessableEntity, "must be paid in USD"},
+		{"no ranks configured", nil, models.RankStats{}, eur(200000), http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewCatService(rankCatDatabase{stats: tt.stats}, validator.New(), staticBreedProvider{}, PayrollPolicy{Ranks: tt.ranks})
+			err, status := cs.checkSalaryBand(1, tt.salary)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			var bandErr *SalaryBandError
+			if !errors.As(err, &bandErr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want a SalaryBandError containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRankLadder(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		wantRanks []string
+		wantErr   string
+	}{
+		{name: "lowest rank first", spec: "recruit:0:0:1000-3000, agent:3:5:3000-6000,veteran:8:20:5000-9000", wantRanks: []string{"recruit", "agent", "veteran"}},
+		{name: "single rank", spec: "recruit:0:0:1000-1000", wantRanks: []string{"recruit"}},
+		{name: "requirements going down", spec: "recruit:0:0:1000-3000,agent:5:5:3000-6000,veteran:4:20:5000-9000", wantErr: `requirements cannot be lower than those of "agent"`},
+		{name: "duplicate rank", spec: "recruit:0:0:1000-3000,recruit:3:5:3000-6000", wantErr: "listed twice"},
+		{name: "first rank with requirements", spec: "recruit:1:0:1000-3000", wantErr: "the first rank cannot have requirements"},
+		{name: "minimum above maximum", spec: "recruit:0:0:3000-1000", wantErr: "minimum is above its maximum"},
+		{name: "missing field", spec: "recruit:0:1000-3000", wantErr: "expected name:min_experience"},
+		{name: "empty name", spec: ":0:0:1000-3000", wantErr: "expected name:min_experience"},
+		{name: "negative experience", spec: "recruit:-1:0:1000-3000", wantErr: "min_experience must be a non-negative integer"},
+		{name: "bad missions", spec: "recruit:0:many:1000-3000", wantErr: "min_completed_missions must be a non-negative integer"},
+		{name: "band without range", spec: "recruit:0:0:1000", wantErr: "salary band must look like"},
+		{name: "bad band amount", spec: "recruit:0:0:abc-3000", wantErr: "salary band minimum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ladder, err := ParseRankLadder(tt.spec, "USD")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ladder) != len(tt.wantRanks) {
+				t.Fatalf("got %d ranks, want %v", len(ladder), tt.wantRanks)
+			}
+			for i, name := range tt.wantRanks {
+				if ladder[i].Name != name || ladder[i].SalaryBand.Min.Currency != "USD" {
+					t.Errorf("rank %d = %+v, want %s in USD", i, ladder[i], name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"spyCat/database/models"
+	"time"
+)
+
+// PayrollPolicy holds the salary rules, with no ranks salaries are not checked against bands and with
+// a zero RaiseApprovalThreshold raises never wait for approval. Raises add up over RaiseApprovalWindow
+type PayrollPolicy struct {
+	Ranks                  models.RankLadder
+	RaiseApprovalThreshold float64
+	RaiseApprovalWindow    time.Duration
+}
+
+// needsApproval reports whether moving the cat from current to salary raises it above the approval threshold,
+// measured from raiseBaseline so a large raise cannot be split into small ones. A currency change cannot be
+// compared so it always needs approval
+func (p PayrollPolicy) needsApproval(current, salary models.Money, history []models.SalaryChange, now time.Time) bool {
+	if p.RaiseApprovalThreshold <= 0 {
+		return false
+	}
+	baseline := p.raiseBaseline(current, history, now)
+	if baseline.Currency != salary.Currency || baseline.Cents == 0 {
+		return true
+	}
+	return float64(salary.Cents-baseline.Cents)*100 > p.RaiseApprovalThreshold*float64(baseline.Cents)
+}
+
+// raiseBaseline is the lowest salary in the current currency the cat had or is set to have within the window:
+// the current salary, the salaries replaced by applied changes and the amounts of applied, scheduled and
+// pending changes. Rejected changes do not count
+func (p PayrollPolicy) raiseBaseline(current models.Money, history []models.SalaryChange, now time.Time) models.Money {
+	baseline := current
+	since := now.Add(-p.RaiseApprovalWindow).Format(models.DateLayout)
+
+	lower := func(salary *models.Money) {
+		if salary != nil && salary.Currency == baseline.Currency && salary.Cents < baseline.Cents {
+			baseline = *salary
+		}
+	}
+	for i := range history {
+		change := &history[i]
+		if change.Status == models.SalaryChangeRejected || change.EffectiveDate < since {
+			continue
+		}
+		if !change.IsScheduled() {
+			lower(change.OldSalary)
+		}
+		lower(&change.NewSalary)
+	}
+	return baseline
+}
+
+// needsApproval checks the raise of the cat against its salary history
+func (cs *CatService) needsApproval(cat *models.Cat, salary models.Money) (bool, error) {
+	if cs.payroll.RaiseApprovalThreshold <= 0 {
+		return false, nil
+	}
+	history, err := cs.DbCat.SelectSalaryHistory(cat.ID)
+	if err != nil {
+		return false, err
+	}
+	return cs.payroll.needsApproval(cat.Salary, salary, history, time.Now()), nil
+}
+
+// ListSalaryChanges returns the salary changes with the given status, pending by default
+func (cs *CatService) ListSalaryChanges(status models.SalaryChangeStatus) ([]models.SalaryChange, error, int) {
+	if status == "" {
+		status = models.SalaryChangePending
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid status %q, use pending, approved or rejected", status), http.StatusBadRequest
+	}
+
+	changes, err := cs.DbCat.SelectSalaryChangesByStatus(status)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	return changes, nil, http.StatusOK
+}
+
+// ReviewSalaryChange approves or rejects a pending salary change. An approved change is applied in the same
+// transaction when its effective date has come, later ones are left to the salary scheduler
+func (cs *CatService) ReviewSalaryChange(id int, approve bool, review models.SalaryReview) (*models.SalaryChange, error, int) {
+	if err := cs.validate.Struct(&review); err != nil {
+		return nil, errors.New("ReviewedBy is required"), http.StatusBadRequest
+	}
+
+	change, err := cs.DbCat.SelectSalaryChange(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no salary change with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if !change.IsPending() {
+		return nil, fmt.Errorf("the salary change is already %s", change.Status), http.StatusConflict
+	}
+
+	status := models.SalaryChangeRejected
+	if approve {
+		status = models.SalaryChangeApproved
+		// the cat's rank may have changed since the request
+		if err, respStatus := cs.checkSalaryBand(change.CatID, change.NewSalary); err != nil {
+			return nil, err, respStatus
+		}
+	}
+
+	change, err = cs.DbCat.ReviewSalaryChange(id, status, review)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("the salary change was reviewed by someone else in the meantime"), http.StatusConflict
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return change, nil, http.StatusOK
+}
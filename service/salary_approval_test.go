@@ -0,0 +1,85 @@
+package service
+
+import (
+	"spyCat/database/models"
+	"testing"
+	"time"
+)
+
+func usd(cents int64) models.Money {
+	return models.Money{Cents: cents, Currency: "USD"}
+}
+
+func usdPtr(cents int64) *models.Money {
+	m := usd(cents)
+	return &m
+}
+
+func TestPayrollPolicyNeedsApproval(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := PayrollPolicy{RaiseApprovalThreshold: 10, RaiseApprovalWindow: 365 * 24 * time.Hour}
+
+	applied := func(oldCents, newCents int64, date string) models.SalaryChange {
+		return models.SalaryChange{OldSalary: usdPtr(oldCents), NewSalary: usd(newCents), EffectiveDate: date,
+			Status: models.SalaryChangeApproved, AppliedAt: "12:00:00 01:01:26"}
+	}
+
+	tests := []struct {
+		name     string
+		disabled bool
+		current  models.Money
+		salary   models.Money
+		history  []models.SalaryChange
+		want     bool
+	}{
+		{name: "small raise", current: usd(100000), salary: usd(109000), want: false},
+		{name: "raise at the threshold", current: usd(100000), salary: usd(110000), want: false},
+		{name: "large raise", current: usd(100000), salary: usd(111000), want: true},
+		{name: "pay cut", current: usd(100000), salary: usd(50000), want: false},
+		{name: "currency change", current: usd(100000), salary: models.Money{Cents: 100000, Currency: "EUR"}, want: true},
+		{name: "approvals disabled", disabled: true, current: usd(100000), salary: usd(500000), want: false},
+		{
+			name:    "second small raise in the window adds up",
+			current: usd(109000), salary: usd(118000),
+			history: []models.SalaryChange{applied(100000, 109000, "2026-03-01")},
+			want:    true,
+		},
+		{
+			name:    "raise before the window does not count",
+			current: usd(109000), salary: usd(118000),
+			history: []models.SalaryChange{applied(100000, 109000, "2025-01-01")},
+			want:    false,
+		},
+		{
+			name:    "rejected change does not count",
+			current: usd(100000), salary: usd(109000),
+			history: []models.SalaryChange{{NewSalary: usd(50000), EffectiveDate: "2026-05-01", Status: models.SalaryChangeRejected}},
+			want:    false,
+		},
+		{
+			name:    "scheduled cut lowers the baseline",
+			current: usd(100000), salary: usd(109000),
+			history: []models.SalaryChange{{NewSalary: usd(90000), EffectiveDate: "2026-07-01", Status: models.SalaryChangeApproved}},
+			want:    true,
+		},
+		{
+			name:    "old salary in another currency is ignored",
+			current: usd(100000), salary: usd(109000),
+			history: []models.SalaryChange{{OldSalary: &models.Money{Cents: 1000, Currency: "JPY"}, NewSalary: usd(100000),
+				EffectiveDate: "2026-05-01", Status: models.SalaryChangeApproved, AppliedAt: "12:00:00 01:05:26"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := policy
+			if tt.disabled {
+				p.RaiseApprovalThreshold = 0
+			}
+			if got := p.needsApproval(tt.current, tt.salary, tt.history, now); got != tt.want {
+				t.Fatalf("needsApproval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// SalaryScheduler applies future-dated salary changes once their effective date arrives
+type SalaryScheduler struct {
+	cats     *CatService
+	interval time.Duration
+}
+
+func NewSalaryScheduler(cats *CatService, interval time.Duration) *SalaryScheduler {
+	return &SalaryScheduler{cats: cats, interval: interval}
+}
+
+// Run applies anything already due, then re-checks on every interval until ctx is cancelled
+func (ss *SalaryScheduler) Run(ctx context.Context) {
+	ss.apply()
+	runEvery(ctx, ss.interval, ss.apply)
+}
+
+func (ss *SalaryScheduler) apply() {
+	applied, err := ss.cats.ApplyScheduledSalaryChanges()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to apply scheduled salary changes")
+	}
+
+	if applied > 0 {
+		log.Info().Msgf("Applied %d scheduled salary changes", applied)
+	}
+}
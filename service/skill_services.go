@@ -0,0 +1,156 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"net/http"
+	"spyCat/database"
+	"spyCat/database/models"
+	"strings"
+)
+
+type SkillServiceInterface interface {
+	ListSkills() ([]models.Skill, error, int)
+	CreateSkill(skill models.Skill) (*models.Skill, error, int)
+	ListCatSkills(catID int) ([]models.CatSkill, error, int)
+	AddCatSkill(catID int, catSkill models.CatSkill) (*models.CatSkill, error, int)
+	UpdateCatSkill(catID, skillID, proficiency int) (*models.CatSkill, error, int)
+	RemoveCatSkill(catID, skillID int) (error, int)
+}
+
+type SkillService struct {
+	DbSkill  database.SkillDatabaseInterface
+	validate *validator.Validate
+}
+
+func NewSkillService(DbSkill database.SkillDatabaseInterface, validate *validator.Validate) *SkillService {
+	return &SkillService{DbSkill: DbSkill, validate: validate}
+}
+
+func (ss *SkillService) ListSkills() ([]models.Skill, error, int) {
+	skills, err := ss.DbSkill.SelectAllSkills()
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return skills, nil, http.StatusOK
+}
+
+func (ss *SkillService) CreateSkill(skill models.Skill) (*models.Skill, error, int) {
+	skill.Name = strings.ToLower(strings.TrimSpace(skill.Name))
+	if err := ss.validate.Struct(&skill); err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+
+	err := ss.DbSkill.InsertSkill(&skill)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+		return nil, errors.New("a skill with that name already exists"), http.StatusConflict
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return &skill, nil, http.StatusCreated
+}
+
+func (ss *SkillService) ListCatSkills(catID int) ([]models.CatSkill, error, int) {
+	if _, err := ss.DbSkill.IsCatArchived(catID); errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	catSkills, err := ss.DbSkill.SelectCatSkills(catID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return catSkills, nil, http.StatusOK
+}
+
+func (ss *SkillService) AddCatSkill(catID int, catSkill models.CatSkill) (*models.CatSkill, error, int) {
+	if err := ss.validate.Struct(&catSkill); err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+	if err, respStatus := ss.checkCatEditable(catID); err != nil {
+		return nil, err, respStatus
+	}
+
+	exists, err := ss.DbSkill.DoesSkillExist(catSkill.SkillID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if !exists {
+		return nil, errors.New("there is no skill with that ID"), http.StatusBadRequest
+	}
+
+	catSkill.CatID = catID
+	err = ss.DbSkill.InsertCatSkill(&catSkill)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+		return nil, errors.New("the cat already has that skill, update its proficiency instead"), http.StatusConflict
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	created, err := ss.DbSkill.SelectCatSkill(catID, catSkill.SkillID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return created, nil, http.StatusCreated
+}
+
+func (ss *SkillService) UpdateCatSkill(catID, skillID, proficiency int) (*models.CatSkill, error, int) {
+	if proficiency < models.MinProficiency || proficiency > models.MaxProficiency {
+		return nil, errors.New("proficiency must be between 1 and 5"), http.StatusBadRequest
+	}
+	if err, respStatus := ss.checkCatEditable(catID); err != nil {
+		return nil, err, respStatus
+	}
+
+	err := ss.DbSkill.UpdateCatSkill(catID, skillID, proficiency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("the cat does not have that skill"), http.StatusNotFound
+	} else if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	updated, err := ss.DbSkill.SelectCatSkill(catID, skillID)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return updated, nil, http.StatusOK
+}
+
+func (ss *SkillService) RemoveCatSkill(catID, skillID int) (error, int) {
+	if err, respStatus := ss.checkCatEditable(catID); err != nil {
+		return err, respStatus
+	}
+
+	err := ss.DbSkill.DeleteCatSkill(catID, skillID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("the cat does not have that skill"), http.StatusNotFound
+	} else if err != nil {
+		return err, http.StatusInternalServerError
+	}
+
+	return nil, http.StatusOK
+}
+
+func (ss *SkillService) checkCatEditable(catID int) (error, int) {
+	archived, err := ss.DbSkill.IsCatArchived(catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("there is no cat with that ID"), http.StatusNotFound
+	} else if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if archived {
+		return errors.New("the cat is archived, restore it before editing its skills"), http.StatusConflict
+	}
+
+	return nil, http.StatusOK
+}
@@ -31,7 +31,7 @@ func (ts *TargetService) UpdateTarget(target *models.Target) (*models.Target, er
 		return nil, err, http.StatusInternalServerError
 	}
 	if missionCompleted {
-		return nil, errors.New("cannot update target of a completed mission"), http.StatusBadRequest
+		return nil, errors.New("cannot update target of a completed or aborted mission"), http.StatusBadRequest
 	}
 
 	if target.Status == "completed" {
@@ -65,7 +65,7 @@ func (ts *TargetService) UpdateTargetNotes(targetID int, notes string) (*models.
 		return nil, err, http.StatusInternalServerError
 	}
 	if missionCompleted {
-		return nil, errors.New("cannot update notes of a target in a completed mission"), http.StatusBadRequest
+		return nil, errors.New("cannot update notes of a target in a completed or aborted mission"), http.StatusBadRequest
 	}
 
 	if target.Status == "completed" {
@@ -99,7 +99,7 @@ func (ts *TargetService) CompleteTarget(missionID, targetID int) (error, int) {
 		return err, http.StatusInternalServerError
 	}
 	if missionCompleted {
-		return errors.New("cannot complete a target of an already completed mission"), http.StatusConflict
+		return errors.New("cannot complete a target of an already completed or aborted mission"), http.StatusConflict
 	}
 
 	return ts.DbTarget.CompleteTarget(missionID, targetID), http.StatusOK
@@ -140,7 +140,7 @@ func (ts *TargetService) AddTarget(missionID int, target *models.Target) (*model
 		return nil, err, http.StatusInternalServerError
 	}
 	if missionCompleted {
-		return nil, errors.New("cannot add target to a completed mission"), http.StatusBadRequest
+		return nil, errors.New("cannot add target to a completed or aborted mission"), http.StatusBadRequest
 	}
 
 	mission, err := ts.DbTarget.GetMission(missionID)
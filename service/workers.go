@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery calls job on every tick until ctx is cancelled
+func runEvery(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job()
+		}
+	}
+}